@@ -0,0 +1,52 @@
+package dur
+
+import "strings"
+
+// baseUnits are the units of duration values like 1h or 3mo.
+var baseUnits = []string{"ps", "ns", "us", "ms", "s", "m", "h", "d", "w", "mo", "y"}
+
+// unitOf returns the unit of a spoken unit name like min, Hours or Minuten, or of a base unit in any case like H.
+// The names of all registered locales are matched case-insensitively, see Locale.
+func unitOf(name string) (string, bool) {
+	for _, u := range baseUnits {
+		if strings.EqualFold(name, u) {
+			return u, true
+		}
+	}
+
+	for _, l := range locales {
+		for u, names := range l.Units {
+			if strings.EqualFold(name, names.One) || strings.EqualFold(name, names.Other) {
+				return u, true
+			}
+		}
+
+		for alias, u := range l.Aliases {
+			if strings.EqualFold(name, alias) {
+				return u, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// alias returns the unit name at the current position including the whitespace before it, like " min" of 90 min.
+// It is empty if there is no known unit name, or if the name is followed by a parenthesis like min( of a call.
+func (s *Scanner) alias() string {
+	var end = s.pos
+	for end < s.len && (s.input[end] == space || s.input[end] == tab) {
+		end++
+	}
+
+	var start = end
+	for end < s.len && isLetter(s.input[end]) {
+		end++
+	}
+
+	if _, ok := unitOf(s.input[start:end]); !ok || end < s.len && s.input[end] == parenOpen {
+		return ""
+	}
+
+	return s.input[s.pos:end]
+}
@@ -0,0 +1,274 @@
+package dur
+
+import "strings"
+
+// Span is the byte range [Start, End) a node covers in the input.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Node is a node of the syntax tree produced by Parse.
+type Node interface {
+	Span() Span
+	String() string
+}
+
+// Literal is a duration, integer, decimal, percentage, timestamp or clock value like 1h, 0,5h, 12, 1,5, 20%,
+// 2026-10-18T09:12 or 09:15.
+type Literal struct {
+	Token Token
+}
+
+func (n *Literal) Span() Span {
+	return Span{Start: n.Token.Pos, End: n.Token.Pos + len(n.Token.Literal)}
+}
+
+func (n *Literal) String() string {
+	return n.Token.Literal
+}
+
+// Unary is a signed operand like -1h.
+type Unary struct {
+	Op Token
+	X  Node
+}
+
+func (n *Unary) Span() Span {
+	return Span{Start: n.Op.Pos, End: n.X.Span().End}
+}
+
+func (n *Unary) String() string {
+	return opSymbol(n.Op.Type) + n.X.String()
+}
+
+// Binary is an operation like 1h+30m. Implicit is set if the operands follow each other without an operator,
+// like in 1h30m, then Op is a TypePlus token positioned at the start of Y.
+type Binary struct {
+	Op       Token
+	X        Node
+	Y        Node
+	Implicit bool
+}
+
+func (n *Binary) Span() Span {
+	return Span{Start: n.X.Span().Start, End: n.Y.Span().End}
+}
+
+func (n *Binary) String() string {
+	if n.Implicit {
+		if _, ok := n.Y.(*Literal); ok {
+			return n.X.String() + n.Y.String()
+		}
+
+		return n.X.String() + " " + n.Y.String()
+	}
+
+	return n.X.String() + " " + opSymbol(n.Op.Type) + " " + n.Y.String()
+}
+
+// Group is a parenthesized expression. X is nil for empty parentheses, which evaluate to zero.
+type Group struct {
+	Lparen int
+	Rparen int
+	X      Node
+}
+
+func (n *Group) Span() Span {
+	return Span{Start: n.Lparen, End: n.Rparen + 1}
+}
+
+func (n *Group) String() string {
+	if n.X == nil {
+		return "()"
+	}
+
+	return "(" + n.X.String() + ")"
+}
+
+// Call is a function call like ceil(7h52m, 15m).
+type Call struct {
+	Fun    Token
+	Lparen int
+	Args   []Node
+	Rparen int
+}
+
+func (n *Call) Span() Span {
+	return Span{Start: n.Fun.Pos, End: n.Rparen + 1}
+}
+
+func (n *Call) String() string {
+	var args = make([]string, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = arg.String()
+	}
+
+	return n.Fun.Literal + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Range is the duration between two times of day like 09:15-17:45 or 09:15..17:45.
+type Range struct {
+	From *Literal
+	Op   Token
+	To   *Literal
+}
+
+func (n *Range) Span() Span {
+	return Span{Start: n.From.Span().Start, End: n.To.Span().End}
+}
+
+func (n *Range) String() string {
+	return n.From.String() + opSymbol(n.Op.Type) + n.To.String()
+}
+
+// List is a list literal like [1h, 2h30m, 45m].
+type List struct {
+	Lbrack int
+	Elems  []Node
+	Rbrack int
+}
+
+func (n *List) Span() Span {
+	return Span{Start: n.Lbrack, End: n.Rbrack + 1}
+}
+
+func (n *List) String() string {
+	var elems = make([]string, len(n.Elems))
+	for i, x := range n.Elems {
+		elems[i] = x.String()
+	}
+
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+// Ident is a reference to a variable like rate.
+type Ident struct {
+	Token Token
+}
+
+func (n *Ident) Span() Span {
+	return Span{Start: n.Token.Pos, End: n.Token.Pos + len(n.Token.Literal)}
+}
+
+func (n *Ident) String() string {
+	return n.Token.Literal
+}
+
+// Assign is an assignment like rate = 45m.
+type Assign struct {
+	Name Token
+	X    Node
+}
+
+func (n *Assign) Span() Span {
+	return Span{Start: n.Name.Pos, End: n.X.Span().End}
+}
+
+func (n *Assign) String() string {
+	return n.Name.Literal + " = " + n.X.String()
+}
+
+// Def is a function definition like def overtime(x: duration) = max(0, x - 8h). Pos is the position of def.
+type Def struct {
+	Pos    int
+	Name   Token
+	Params []Param
+	X      Node
+}
+
+// Param is a parameter of a function definition. Type is empty if the parameter accepts any value.
+type Param struct {
+	Name Token
+	Type Token
+}
+
+func (n *Def) Span() Span {
+	return Span{Start: n.Pos, End: n.X.Span().End}
+}
+
+func (n *Def) String() string {
+	var params = make([]string, len(n.Params))
+	for i, param := range n.Params {
+		params[i] = param.Name.Literal
+		if param.Type.Literal != "" {
+			params[i] += ": " + param.Type.Literal
+		}
+	}
+
+	return def + " " + n.Name.Literal + "(" + strings.Join(params, ", ") + ") = " + n.X.String()
+}
+
+// Statements is a list of statements separated by semicolons or newlines like rate = 45m; rate*7.
+type Statements struct {
+	List []Node
+}
+
+func (n *Statements) Span() Span {
+	return Span{Start: n.List[0].Span().Start, End: n.List[len(n.List)-1].Span().End}
+}
+
+func (n *Statements) String() string {
+	var list = make([]string, len(n.List))
+	for i, x := range n.List {
+		list[i] = x.String()
+	}
+
+	return strings.Join(list, "; ")
+}
+
+// Inspect traverses the tree rooted at node in depth-first order. It calls f for each node,
+// children are only visited if f returns true.
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Unary:
+		Inspect(n.X, f)
+	case *Binary:
+		Inspect(n.X, f)
+		Inspect(n.Y, f)
+	case *Group:
+		Inspect(n.X, f)
+	case *Call:
+		for _, arg := range n.Args {
+			Inspect(arg, f)
+		}
+	case *List:
+		for _, x := range n.Elems {
+			Inspect(x, f)
+		}
+	case *Range:
+		Inspect(n.From, f)
+		Inspect(n.To, f)
+	case *Assign:
+		Inspect(n.X, f)
+	case *Def:
+		Inspect(n.X, f)
+	case *Statements:
+		for _, x := range n.List {
+			Inspect(x, f)
+		}
+	}
+}
+
+func opSymbol(t TokenType) string {
+	switch t {
+	case TypePlus:
+		return string(plus)
+	case TypeMinus:
+		return string(minus)
+	case TypeMultiply:
+		return string(multiply)
+	case TypeDivide:
+		return string(divide)
+	case TypeModulo:
+		return modulo
+	case TypeRange:
+		return rangeDots
+	default:
+		return string(t)
+	}
+}
@@ -0,0 +1,758 @@
+package dur
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// previous is the name of the variable that holds the value of the previous statement.
+const previous = "_"
+
+func NewCalculator(input string, opts ...Option) *Calculator {
+	options := newOptions(opts)
+
+	return &Calculator{
+		input:    input,
+		p:        options.p,
+		legacy:   options.legacyPrecedence,
+		units:    options.units,
+		from:     options.from,
+		divMod:   options.divMod,
+		rounding: options.rounding,
+		saturate: options.saturate,
+		big:      options.big,
+		env:      options.env,
+		limit:    options.recursionLimit,
+		colon:    options.colonDurations,
+		formatter: formatter{
+			format:      options.format,
+			unit:        options.outputUnit,
+			decimals:    options.decimals,
+			maxUnit:     options.maxUnit,
+			units:       options.units,
+			lang:        options.lang,
+			approximate: options.approximate,
+			granularity: new(big.Int).Mul(big.NewInt(int64(options.granularity)), big.NewInt(picosecondsPerNano)),
+		},
+	}
+}
+
+// Calculator parses an input into a syntax tree and evaluates it.
+type Calculator struct {
+	input     string
+	root      Node
+	parsed    bool
+	p         printer
+	legacy    bool
+	units     units
+	from      time.Time
+	divMod    bool
+	rounding  RoundingMode
+	saturate  bool
+	big       bool
+	env       *Env
+	scope     *Env
+	limit     int
+	depth     int
+	colon     bool
+	formatter formatter
+}
+
+// Calculate evaluates the input, which must result in a duration.
+func (i *Calculator) Calculate() (time.Duration, error) {
+	r, err := i.Evaluate()
+	if err != nil {
+		return 0, err
+	}
+
+	if !r.IsDuration() {
+		return 0, newTokenError(KindInvalidResult, Token{}, "result is no duration")
+	}
+
+	d, ok := r.Duration()
+	if !ok {
+		return 0, newTokenError(KindOverflow, Token{}, "result exceeds the range of a duration")
+	}
+
+	return d, nil
+}
+
+// Evaluate evaluates the input, which may result in a duration or a number.
+func (i *Calculator) Evaluate() (Result, error) {
+	if err := i.parse(); err != nil {
+		return Result{}, err
+	}
+
+	if err := i.formatter.validate(); err != nil {
+		return Result{}, err
+	}
+
+	i.scope = NewEnv(i.env)
+
+	if i.divMod {
+		return i.evaluateDivMod(i.root)
+	}
+
+	v, err := i.evaluate(i.root)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{v: i.result(v), f: i.formatter}, nil
+}
+
+// parse scans and parses the input once.
+func (i *Calculator) parse() error {
+	if i.parsed {
+		return nil
+	}
+
+	s := NewScanner(i.input)
+	s.colonDurations = i.colon
+
+	tokens, err := s.Tokens()
+	if err != nil {
+		return err
+	}
+
+	if i.root, err = parse(tokens, i.legacy); err != nil {
+		return err
+	}
+
+	i.parsed = true
+
+	return nil
+}
+
+// result rounds an exact duration, or the durations of a list, to whole nanoseconds, or picoseconds with BigPrecision.
+// Timestamps are rounded to nanoseconds and returned as time.Time, times of day are rounded to nanoseconds.
+func (i *Calculator) result(v interface{}) interface{} {
+	return i.rounding.result(v, i.big)
+}
+
+// result rounds v like Calculator.result. Durations that exceed the range of time.Duration are
+// rounded to picoseconds like with BigPrecision.
+func (m RoundingMode) result(v interface{}, bigPrecision bool) interface{} {
+	if l, ok := v.(list); ok {
+		vr, _ := mapList(l, func(e interface{}) (interface{}, error) {
+			return m.result(e, bigPrecision), nil
+		})
+
+		return vr
+	}
+
+	if ts, ok := v.(timestamp); ok {
+		return ts.time(m)
+	}
+
+	if c, ok := v.(clock); ok {
+		return clock{ns: wrapDay(new(big.Rat).SetInt(m.round(c.ns)))}
+	}
+
+	d, ok := v.(duration)
+
+	switch {
+	case ok && (bigPrecision || !m.round(d.ns).IsInt64()):
+		return m.picoseconds(d)
+	case ok:
+		return m.toDuration(d)
+	}
+
+	return v
+}
+
+// evaluateDivMod evaluates a division at the root of the tree, or of the last statement,
+// into its integral quotient and remainder.
+func (i *Calculator) evaluateDivMod(node Node) (Result, error) {
+	if s, ok := node.(*Statements); ok {
+		last := len(s.List) - 1
+
+		if _, err := i.evaluate(&Statements{List: s.List[:last]}); err != nil {
+			return Result{}, err
+		}
+
+		node = s.List[last]
+	}
+
+	for {
+		g, ok := node.(*Group)
+		if !ok {
+			break
+		}
+
+		node = g.X
+	}
+
+	n, ok := node.(*Binary)
+	if !ok || n.Op.Type != TypeDivide {
+		return Result{}, newError(KindInvalidResult, 0, "divmod requires a division")
+	}
+
+	v1, err := i.evaluate(n.X)
+	if err != nil {
+		return Result{}, err
+	}
+
+	v2, err := i.evaluate(n.Y)
+	if err != nil {
+		return Result{}, err
+	}
+
+	q, r, err := divMod(v1, v2, n.Op)
+	if err != nil {
+		return Result{}, err
+	}
+
+	i.p.print(v1, v2, q, opSymbol(TypeDivide))
+	i.p.print(v1, v2, r, modulo)
+
+	return Result{v: i.result(q), rem: i.result(r), f: i.formatter}, nil
+}
+
+// evaluate walks the tree rooted at node. An empty tree evaluates to zero.
+func (i *Calculator) evaluate(node Node) (interface{}, error) {
+	switch n := node.(type) {
+	case nil:
+		return durationOf(0), nil
+	case *Literal:
+		return i.literal(n.Token)
+	case *Group:
+		return i.evaluate(n.X)
+	case *Unary:
+		if c, ok, err := i.months(n); err != nil || ok {
+			if err != nil {
+				return nil, err
+			}
+
+			return i.resolveMonths(n.String(), c, n.Op)
+		}
+
+		v, err := i.evaluate(n.X)
+		if err != nil || n.Op.Type == TypePlus {
+			return v, err
+		}
+
+		switch v.(type) {
+		case timestamp, clock:
+			return nil, newTokenError(KindInvalidOperation, n.Op, "cannot negate a %v", typeName(v))
+		}
+
+		return i.fit(negate(v), n.Op)
+	case *Binary:
+		// months and years are added up before they are resolved, so 1mo + 1mo is the same as 2mo
+		if c, ok, err := i.months(n); err != nil || ok {
+			if err != nil {
+				return nil, err
+			}
+
+			return i.resolveMonths(n.String(), c, n.Op)
+		}
+
+		v1, err := i.evaluate(n.X)
+		if err != nil {
+			return nil, err
+		}
+
+		v2, err := i.evaluate(n.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return i.apply(n.Op, v1, v2)
+	case *Call:
+		return i.call(n)
+	case *Range:
+		return i.clockRange(n)
+	case *List:
+		var l = make(list, len(n.Elems))
+
+		for e, x := range n.Elems {
+			v, err := i.evaluate(x)
+			if err != nil {
+				return nil, err
+			}
+
+			l[e] = v
+		}
+
+		return l, nil
+	case *Ident:
+		v, ok := i.scope.lookup(n.Token.Literal)
+		if !ok {
+			return nil, newTokenError(KindUndefined, n.Token, "undefined variable '%v'", n.Token.Literal)
+		}
+
+		return v, nil
+	case *Assign:
+		v, err := i.evaluate(n.X)
+		if err != nil {
+			return nil, err
+		}
+
+		i.scope.set(n.Name.Literal, v)
+
+		return v, nil
+	case *Statements:
+		return i.statements(n)
+	case *Def:
+		if err := i.define(n); err != nil {
+			return nil, err
+		}
+
+		return durationOf(0), nil
+	default:
+		return nil, newError(KindUnexpectedToken, node.Span().Start, "unknown node %T", node)
+	}
+}
+
+// clockRange evaluates the duration between two times of day.
+func (i *Calculator) clockRange(n *Range) (interface{}, error) {
+	from, err := i.literal(n.From.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := i.literal(n.To.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	vr := from.(clock).between(to.(clock))
+
+	i.p.printRange(from.(clock), to.(clock), vr)
+
+	return vr, nil
+}
+
+// statements evaluates each statement and returns the value of the last one. The value of the previous
+// statement is available as _, the value of statement n as _n. Function definitions have no value.
+func (i *Calculator) statements(n *Statements) (interface{}, error) {
+	var v interface{} = durationOf(0)
+
+	for s, stmt := range n.List {
+		if d, ok := stmt.(*Def); ok {
+			if err := i.define(d); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		var err error
+		if v, err = i.evaluate(stmt); err != nil {
+			return nil, err
+		}
+
+		i.scope.set(previous, v)
+		i.scope.set(previous+strconv.Itoa(s+1), v)
+	}
+
+	return v, nil
+}
+
+func (i *Calculator) apply(op Token, v1, v2 interface{}) (interface{}, error) {
+	vr, err := operate(op, v1, v2)
+	if err != nil {
+		return nil, err
+	}
+
+	if vr, err = i.fit(vr, op); err != nil {
+		return nil, err
+	}
+
+	i.p.print(v1, v2, vr, opSymbol(op.Type))
+
+	return vr, nil
+}
+
+// operate applies the operator op to v1 and v2, element-wise if one of them is a list.
+func operate(op Token, v1, v2 interface{}) (interface{}, error) {
+	var (
+		vr  interface{}
+		err error
+	)
+
+	_, isPercent1 := v1.(percent)
+	_, isPercent2 := v2.(percent)
+	_, isList1 := v1.(list)
+	_, isList2 := v2.(list)
+	_, isTime1 := v1.(timestamp)
+	_, isTime2 := v2.(timestamp)
+	_, isClock1 := v1.(clock)
+	_, isClock2 := v2.(clock)
+
+	switch {
+	case isList1 || isList2:
+		vr, err = elementWise(op, v1, v2)
+	case isTime1 || isTime2:
+		vr, err = applyTime(op, v1, v2)
+	case isClock1 || isClock2:
+		vr, err = applyClock(op, v1, v2)
+	case isPercent1 || isPercent2:
+		vr, err = applyPercent(v1, v2, op)
+	case op.Type == TypePlus:
+		vr, err = add(v1, v2, op)
+	case op.Type == TypeMinus:
+		vr, err = sub(v1, v2, op)
+	case op.Type == TypeMultiply:
+		vr, err = mul(v1, v2, op)
+	case op.Type == TypeDivide:
+		vr, err = div(v1, v2, op)
+	case op.Type == TypeModulo:
+		vr, err = mod(v1, v2, op)
+	default:
+		return nil, newTokenError(KindUnexpectedToken, op, "unknown operator '%v'", op.Type)
+	}
+
+	return vr, err
+}
+
+// fit checks that a duration fits into the range of time.Duration. Values out of range are clamped
+// if Saturate is set, otherwise they are reported as overflow of the operation or literal tok.
+// With BigPrecision the range is not limited.
+func (i *Calculator) fit(v interface{}, tok Token) (interface{}, error) {
+	if l, ok := v.(list); ok {
+		return mapList(l, func(e interface{}) (interface{}, error) {
+			return i.fit(e, tok)
+		})
+	}
+
+	d, ok := v.(duration)
+	if !ok || i.big || i.rounding.round(d.ns).IsInt64() {
+		return v, nil
+	}
+
+	if i.saturate {
+		return clamp(d), nil
+	}
+
+	if tok.Literal != "" {
+		return nil, newTokenError(KindOverflow, tok, "overflow in '%v': value exceeds the range of a duration", tok.Literal)
+	}
+
+	return nil, newTokenError(KindOverflow, tok, "overflow in '%v': result exceeds the range of a duration", opSymbol(tok.Type))
+}
+
+// iso8601 evaluates an ISO 8601 duration, years and months are printed like the units y and mo.
+func (i *Calculator) iso8601(tok Token) (interface{}, error) {
+	d, to, err := parseISO8601(tok.Literal, i.from)
+	if err != nil {
+		return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+	}
+
+	dur, err := i.fit(d, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	if !to.IsZero() {
+		i.p.printCalendar(tok.Literal, i.from, to, dur.(duration))
+	}
+
+	return dur, nil
+}
+
+// calendar is a sum of months and an exact rest like 1mo + 1h or P1M1D, the months are resolved from the anchor date.
+type calendar struct {
+	months int
+	ns     *big.Rat
+}
+
+// months adds up the months of a sum or difference of values of the units mo and y or ISO 8601 durations with
+// years or months like 1y2mo, 1mo + 1mo, -(1mo) or P1M + P1M1D. It reports false if node is anything else.
+func (i *Calculator) months(node Node) (calendar, bool, error) {
+	switch n := node.(type) {
+	case *Literal:
+		if n.Token.Type == TypeDuration && isISO8601(n.Token.Literal) {
+			months, ns, err := splitISO8601(n.Token.Literal, i.from)
+			if err != nil {
+				return calendar{}, false, newTokenError(KindInvalidValue, n.Token, "%v", err)
+			}
+
+			return calendar{months: months, ns: ns}, months != 0, nil
+		}
+
+		if n.Token.Type != TypeDuration || !isCalendarUnit(n.Token.Literal) {
+			return calendar{}, false, nil
+		}
+
+		months, err := calendarMonths(n.Token.Literal, i.from)
+		if err != nil {
+			return calendar{}, false, newTokenError(KindInvalidValue, n.Token, "%v", err)
+		}
+
+		return calendar{months: months, ns: new(big.Rat)}, true, nil
+	case *Binary:
+		if n.Op.Type != TypePlus && n.Op.Type != TypeMinus {
+			return calendar{}, false, nil
+		}
+
+		c1, ok1, err := i.months(n.X)
+		if err != nil || !ok1 {
+			return calendar{}, false, err
+		}
+
+		c2, ok2, err := i.months(n.Y)
+		if err != nil || !ok2 {
+			return calendar{}, false, err
+		}
+
+		if n.Op.Type == TypeMinus {
+			return calendar{months: c1.months - c2.months, ns: c1.ns.Sub(c1.ns, c2.ns)}, true, nil
+		}
+
+		return calendar{months: c1.months + c2.months, ns: c1.ns.Add(c1.ns, c2.ns)}, true, nil
+	case *Group:
+		return i.months(n.X)
+	case *Unary:
+		c, ok, err := i.months(n.X)
+		if ok && n.Op.Type == TypeMinus {
+			c = calendar{months: -c.months, ns: c.ns.Neg(c.ns)}
+		}
+
+		return c, ok, err
+	default:
+		return calendar{}, false, nil
+	}
+}
+
+// resolveMonths returns the duration from the anchor date to the date that lies c.months after it, plus the rest.
+func (i *Calculator) resolveMonths(lit string, c calendar, tok Token) (interface{}, error) {
+	to := addMonths(i.from, c.months)
+	d := durationBetween(i.from, to)
+
+	dur, err := i.fit(duration{ns: d.ns.Add(d.ns, c.ns)}, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	i.p.printCalendar(lit, i.from, to.Add(time.Duration(truncate(c.ns).Int64())), dur.(duration))
+
+	return dur, nil
+}
+
+func (i *Calculator) literal(tok Token) (interface{}, error) {
+	switch tok.Type {
+	case TypeDuration:
+		if isISO8601(tok.Literal) {
+			return i.iso8601(tok)
+		}
+
+		if isCalendarUnit(tok.Literal) {
+			months, err := calendarMonths(tok.Literal, i.from)
+			if err != nil {
+				return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+			}
+
+			return i.resolveMonths(tok.Literal, calendar{months: months, ns: new(big.Rat)}, tok)
+		}
+
+		dur, err := parseDuration(tok.Literal, i.units)
+		if err != nil {
+			return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+		}
+
+		return i.fit(dur, tok)
+	case TypeInteger:
+		v, err := parseDecimal(tok.Literal)
+		if err != nil {
+			return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+		}
+
+		return normalize(v), nil
+	case TypeDecimal:
+		v, err := parseDecimal(tok.Literal)
+		if err != nil {
+			return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+		}
+
+		return v, nil
+	case TypePercent:
+		v, err := parsePercent(tok.Literal)
+		if err != nil {
+			return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+		}
+
+		return v, nil
+	case TypeTimestamp:
+		v, err := parseTimestamp(tok.Literal)
+		if err != nil {
+			return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+		}
+
+		return v, nil
+	case TypeClock:
+		v, err := parseClock(tok.Literal)
+		if err != nil {
+			return nil, newTokenError(KindInvalidValue, tok, "%v", err)
+		}
+
+		return v, nil
+	default:
+		return nil, newTokenError(KindUnexpectedToken, tok, "unexpected token '%v'", tok.Type)
+	}
+}
+
+func negate(v interface{}) interface{} {
+	switch n := v.(type) {
+	case duration:
+		return duration{ns: new(big.Rat).Neg(n.ns)}
+	case int:
+		return -n
+	case *big.Rat:
+		return new(big.Rat).Neg(n)
+	case percent:
+		return percent{r: new(big.Rat).Neg(n.r)}
+	case list:
+		vr, _ := mapList(n, func(e interface{}) (interface{}, error) {
+			return negate(e), nil
+		})
+
+		return vr
+	default:
+		return v
+	}
+}
+
+func add(v1, v2 interface{}, op Token) (duration, error) {
+	d1, err := asDuration(v1, op)
+	if err != nil {
+		return duration{}, err
+	}
+
+	d2, err := asDuration(v2, op)
+	if err != nil {
+		return duration{}, err
+	}
+
+	return duration{ns: new(big.Rat).Add(d1.ns, d2.ns)}, nil
+}
+
+func sub(v1, v2 interface{}, op Token) (duration, error) {
+	d1, err := asDuration(v1, op)
+	if err != nil {
+		return duration{}, err
+	}
+
+	d2, err := asDuration(v2, op)
+	if err != nil {
+		return duration{}, err
+	}
+
+	return duration{ns: new(big.Rat).Sub(d1.ns, d2.ns)}, nil
+}
+
+// div divides durations and scalars exactly. The ratio of two durations is a number.
+func div(v1, v2 interface{}, op Token) (interface{}, error) {
+	if isZero(v2) {
+		return nil, newTokenError(KindInvalidOperation, op, "division by zero")
+	}
+
+	switch {
+	case isScalar(v1) && isScalar(v2):
+		return normalize(new(big.Rat).Quo(toRat(v1), toRat(v2))), nil
+	case isScalar(v1):
+		return nil, newTokenError(KindInvalidOperation, op, "cannot divide by a duration")
+	case isScalar(v2):
+		return duration{ns: new(big.Rat).Quo(toRat(v1), toRat(v2))}, nil
+	default:
+		return normalize(new(big.Rat).Quo(toRat(v1), toRat(v2))), nil
+	}
+}
+
+// applyPercent applies a percentage to the value it follows: x + 20% is x * 1.2, x - 15% is x * 0.85,
+// x * 80% is x * 0.8 and x / 50% is x / 0.5. Percentages can be added to and subtracted from each other.
+func applyPercent(v1, v2 interface{}, op Token) (interface{}, error) {
+	p1, isPercent1 := v1.(percent)
+	p2, isPercent2 := v2.(percent)
+
+	switch {
+	case isPercent1 && isPercent2 && op.Type == TypePlus:
+		return percent{r: new(big.Rat).Add(p1.r, p2.r)}, nil
+	case isPercent1 && isPercent2 && op.Type == TypeMinus:
+		return percent{r: new(big.Rat).Sub(p1.r, p2.r)}, nil
+	case isPercent1 && isPercent2:
+	case isPercent2 && op.Type == TypePlus:
+		return mul(v1, new(big.Rat).Add(big.NewRat(1, 1), p2.r), op)
+	case isPercent2 && op.Type == TypeMinus:
+		return mul(v1, new(big.Rat).Sub(big.NewRat(1, 1), p2.r), op)
+	case isPercent2 && op.Type == TypeMultiply:
+		return mul(v1, p2.r, op)
+	case isPercent2 && op.Type == TypeDivide:
+		return div(v1, p2.r, op)
+	case isPercent1 && op.Type == TypeMultiply:
+		return mul(p1.r, v2, op)
+	}
+
+	return nil, newTokenError(KindInvalidOperation, op, "cannot apply percentage with '%v'", opSymbol(op.Type))
+}
+
+// mod returns the remainder of a truncated division of durations or integers, it has the sign of v1.
+func mod(v1, v2 interface{}, op Token) (interface{}, error) {
+	_, r, err := divMod(v1, v2, op)
+
+	return r, err
+}
+
+// divMod returns the quotient of a division truncated to an integer and the remainder.
+// Dividing a duration by an integer truncates the quotient to whole nanoseconds.
+func divMod(v1, v2 interface{}, op Token) (interface{}, interface{}, error) {
+	if isZero(v2) {
+		return nil, nil, newTokenError(KindInvalidOperation, op, "division by zero")
+	}
+
+	switch a := v1.(type) {
+	case duration:
+		switch b := v2.(type) {
+		case duration:
+			q := new(big.Rat).SetInt(truncate(new(big.Rat).Quo(a.ns, b.ns)))
+			r := new(big.Rat).Sub(a.ns, new(big.Rat).Mul(q, b.ns))
+
+			return normalize(q), duration{ns: r}, nil
+		case int:
+			q := new(big.Rat).SetInt(truncate(new(big.Rat).Quo(a.ns, toRat(b))))
+			r := new(big.Rat).Sub(a.ns, new(big.Rat).Mul(q, toRat(b)))
+
+			return duration{ns: q}, duration{ns: r}, nil
+		}
+	case int:
+		switch b := v2.(type) {
+		case int:
+			return a / b, a % b, nil
+		case duration:
+			return nil, nil, newTokenError(KindInvalidOperation, op, "cannot divide by a duration")
+		}
+	}
+
+	return nil, nil, newTokenError(KindInvalidOperation, op, "modulo requires durations or integers")
+}
+
+func mul(v1, v2 interface{}, op Token) (interface{}, error) {
+	if isScalar(v1) && !isScalar(v2) {
+		v1, v2 = v2, v1
+	}
+
+	switch {
+	case isScalar(v1):
+		return normalize(new(big.Rat).Mul(toRat(v1), toRat(v2))), nil
+	case isScalar(v2):
+		return duration{ns: new(big.Rat).Mul(toRat(v1), toRat(v2))}, nil
+	default:
+		return nil, newTokenError(KindInvalidOperation, op, "cannot calculate 2 durations")
+	}
+}
+
+// asDuration returns v if it is a duration. tok is the operator that requires a duration operand,
+// it is empty if v is the final result.
+func asDuration(v interface{}, tok Token) (duration, error) {
+	if d, ok := v.(duration); ok {
+		return d, nil
+	}
+
+	if tok.Type == TypeEmpty {
+		return duration{}, newTokenError(KindInvalidResult, tok, "result is no duration")
+	}
+
+	return duration{}, newTokenError(KindInvalidOperation, tok, "result is no duration")
+}
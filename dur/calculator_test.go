@@ -0,0 +1,1386 @@
+package dur_test
+
+import (
+	"errors"
+	"github.com/Oppodelldog/dur/dur"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCalculator_Calculate(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "single value hours", input: "0h", want: "0s"},
+		{name: "single value hours", input: "1h", want: "1h0m0s"},
+		{name: "single value hours", input: "61h", want: "61h0m0s"},
+
+		{name: "single value minutes", input: "0m", want: "0s"},
+		{name: "single value minutes", input: "1m", want: "1m0s"},
+		{name: "single value minutes", input: "61m", want: "1h1m0s"},
+
+		{name: "single value seconds", input: "0s", want: "0s"},
+		{name: "single value seconds", input: "1s", want: "1s"},
+		{name: "single value seconds", input: "61s", want: "1m1s"},
+
+		{name: "single value float hours", input: "0,166666666666667h", want: "10m0s"},
+		{name: "single value float hours", input: "0,16666666666666h", want: "9m59.999999999s"},
+		{name: "single value float hours", input: "1h", want: "1h0m0s"},
+		{name: "single value float hours", input: "1,25h", want: "1h15m0s"},
+		{name: "single value float hours", input: "1,5h", want: "1h30m0s"},
+		{name: "single value float hours", input: "1,75h", want: "1h45m0s"},
+		{name: "single value float hours", input: "2.0h", want: "2h0m0s"},
+
+		{name: "single value float minutes", input: "10,5m", want: "10m30s"},
+		{name: "single value float seconds", input: "10,005s", want: "10.005s"},
+
+		{name: "single value float milliseconds", input: "10,5ms", want: "10.5ms"},
+		{name: "single value float milliseconds", input: "10,5ms+0,5ms", want: "11ms"},
+
+		{name: "single value float microseconds", input: "10,5us", want: "10.5µs"},
+		{name: "single value float microseconds", input: "10,5us+0,5us", want: "11µs"},
+
+		{name: "all units", input: "1h1m1s1ms1us1ns", want: "1h1m1.001001001s"},
+		{name: "all units", input: "1w1d1h1m1s1ms1us1ns", want: "193h1m1.001001001s"},
+
+		{name: "days", input: "3d", want: "72h0m0s"},
+		{name: "days", input: "1,5d", want: "36h0m0s"},
+		{name: "weeks", input: "2w", want: "336h0m0s"},
+		{name: "weeks", input: "0.5w", want: "84h0m0s"},
+		{name: "days and weeks", input: "3d + 2w", want: "408h0m0s"},
+
+		{name: "add", input: "1h+12m", want: "1h12m0s"},
+		{name: "subtract", input: "1h-12m", want: "48m0s"},
+		{name: "subtract add", input: "1h-12m+3h", want: "3h48m0s"},
+
+		{name: "subtract float hours", input: "2,0h-0,5h", want: "1h30m0s"},
+		{name: "subtract float hour from value", input: "30m-0,5h", want: "0s"},
+		{name: "subtract float value from float hour", input: "0,5h-30m", want: "0s"},
+
+		{name: "add float hours", input: "2,0h+0,5h", want: "2h30m0s"},
+		{name: "add float hour to value", input: "30m+0,5h", want: "1h0m0s"},
+		{name: "add float value to float hour", input: "0,5h+30m", want: "1h0m0s"},
+
+		{name: "add milliseconds", input: "1s+2ms", want: "1.002s"},
+		{name: "add microseconds", input: "1s+2us", want: "1.000002s"},
+		{name: "add nanoseconds", input: "1s+2ns", want: "1.000000002s"},
+		{name: "add nanoseconds", input: "1s+2000ns", want: "1.000002s"},
+		{name: "add nanoseconds", input: "1s+1000000000ns", want: "2s"},
+		{name: "add all", input: "1h1m1s999ms999us999ns + 1ns", want: "1h1m2s"},
+
+		{name: "add float seconds", input: "9m59.999999999s+0.000000001s", want: "10m0s"},
+
+		{name: "decimals are exact", input: "3.01s + 0s", want: "3.01s"},
+		{name: "decimals are exact", input: "0,1h + 0,2h", want: "18m0s"},
+		{name: "fractions of nanoseconds", input: "0,5ns + 0,5ns", want: "1ns"},
+		{name: "fractions of nanoseconds", input: "0,4ns + 0,4ns", want: "0s"},
+		{name: "exact division", input: "1h/3*3", want: "1h0m0s"},
+		{name: "exact division", input: "1ns/3*3", want: "1ns"},
+		{name: "exact division", input: "1h/7+1h/7*6", want: "1h0m0s"},
+		{name: "exact integer division", input: "7/2*1h", want: "3h30m0s"},
+
+		{name: "multiple value concat", input: "1h30m", want: "1h30m0s"},
+		{name: "multiple value concat in subtraction", input: "10h - 1h30m", want: "8h30m0s"},
+
+		{name: "parentheses", input: "()", want: "0s"},
+		{name: "parentheses", input: "(1h)", want: "1h0m0s"},
+		{name: "parentheses", input: "(-1h)", want: "-1h0m0s"},
+		{name: "parentheses", input: "-(1h)", want: "-1h0m0s"},
+		{name: "parentheses", input: "-(+1h)", want: "-1h0m0s"},
+		{name: "parentheses", input: "(-1h-1h)", want: "-2h0m0s"},
+		{name: "parentheses", input: "(-1h)+(-1h)", want: "-2h0m0s"},
+		{name: "parentheses", input: "(-1h)-(-1h)", want: "0s"},
+		{name: "parentheses", input: "(-1h+-1h)", want: "-2h0m0s"},
+		{name: "parentheses", input: "(-1h-+1h)", want: "-2h0m0s"},
+		{name: "parentheses", input: "1h(())1h()1h(())", want: "3h0m0s"},
+		{name: "parentheses", input: "1h(1h(1h))", want: "3h0m0s"},
+		{name: "parentheses", input: "1h(10m)", want: "1h10m0s"},
+		{name: "parentheses", input: "1h(10m)", want: "1h10m0s"},
+		{name: "parentheses", input: "1h(10m+20m)", want: "1h30m0s"},
+		{name: "parentheses", input: "2h-(1h30m)", want: "30m0s"},
+		{name: "parentheses", input: "2h-(1h+30m)", want: "30m0s"},
+		{name: "parentheses", input: "2h-(1h+30m)", want: "30m0s"},
+		{name: "parentheses", input: "(2h-(1h+30m))", want: "30m0s"},
+		{name: "parentheses", input: "(2h)-(1h+30m)", want: "30m0s"},
+		{name: "parentheses", input: "((2h)-(1h+30m))", want: "30m0s"},
+
+		{name: "multiply", input: "2*1m", want: "2m0s"},
+		{name: "multiply", input: "2*2*1m", want: "4m0s"},
+		{name: "multiply", input: "10m+20m*2", want: "50m0s"},
+		{name: "multiply", input: "10m*6", want: "1h0m0s"},
+		{name: "multiply", input: "10m*2+40m", want: "1h0m0s"},
+
+		{name: "divide", input: "2/1*1m", want: "2m0s"},
+		{name: "divide", input: "1m/2", want: "30s"},
+		{name: "divide", input: "1h/4", want: "15m0s"},
+		{name: "divide", input: "40h/5", want: "8h0m0s"},
+		{name: "divide", input: "1h/2", want: "30m0s"},
+		{name: "divide", input: "1h/12", want: "5m0s"},
+		{name: "divide", input: "1h/11", want: "5m27.272727272s"},
+
+		{name: "decimal multiplier", input: "8h*1.5", want: "12h0m0s"},
+		{name: "decimal multiplier", input: "8h*1,5", want: "12h0m0s"},
+		{name: "decimal multiplier", input: "1,5*8h", want: "12h0m0s"},
+		{name: "decimal multiplier", input: "1.5*2*1h", want: "3h0m0s"},
+		{name: "decimal multiplier", input: "-0.5*1h", want: "-30m0s"},
+		{name: "decimal divisor", input: "1h/2.5", want: "24m0s"},
+		{name: "decimal divisor", input: "1h/0,5", want: "2h0m0s"},
+		{name: "decimal divisor", input: "1.5/3*1h", want: "30m0s"},
+		{name: "decimal truncates to nanoseconds", input: "1ns*1.5", want: "1ns"},
+		{name: "decimal truncates to nanoseconds", input: "-1ns*1.5", want: "-1ns"},
+		{name: "decimal divisor", input: "1h/3.0", want: "20m0s"},
+
+		{name: "modulo", input: "3h mod 25m", want: "5m0s"},
+		{name: "modulo", input: "1h mod 11", want: "8ns"},
+		{name: "modulo", input: "-7m mod 3m", want: "-1m0s"},
+		{name: "modulo", input: "7 mod 3 * 1h", want: "1h0m0s"},
+		{name: "modulo", input: "1h + 50m mod 25m", want: "1h0m0s"},
+
+		{name: "percent", input: "1h + 20%", want: "1h12m0s"},
+		{name: "percent", input: "40h * 80%", want: "32h0m0s"},
+		{name: "percent", input: "80% * 40h", want: "32h0m0s"},
+		{name: "percent", input: "1h - 15%", want: "51m0s"},
+		{name: "percent", input: "1h / 50%", want: "2h0m0s"},
+		{name: "percent", input: "1h + 12,5%", want: "1h7m30s"},
+		{name: "percent", input: "1h + 10% + 10%", want: "1h12m36s"},
+		{name: "percent", input: "1h + (10% + 10%)", want: "1h12m0s"},
+		{name: "percent", input: "2h + 30m * 10%", want: "2h3m0s"},
+		{name: "percent", input: "1h + -10%", want: "54m0s"},
+
+		{name: "precedence", input: "1h+2*30m", want: "2h0m0s"},
+		{name: "precedence", input: "1h-10m*3", want: "30m0s"},
+		{name: "precedence", input: "1h+1h/4-10m", want: "1h5m0s"},
+		{name: "precedence", input: "(10m+20m)*2", want: "1h0m0s"},
+		{name: "precedence", input: "2*(1h-30m)/3", want: "20m0s"},
+		{name: "precedence", input: "1h30m*2", want: "3h0m0s"},
+		{name: "precedence", input: "-1h30m*2", want: "-3h0m0s"},
+		{name: "precedence", input: "-1h*2+3h", want: "1h0m0s"},
+		{name: "precedence", input: "8h*5-2h30m", want: "37h30m0s"},
+		{name: "precedence", input: "(2*3)*1h", want: "6h0m0s"},
+
+		{name: "empty", input: "", want: "0s"},
+		{name: "missing operand", input: "0h+", want: "0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input).Calculate()
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got.String(), tt.want) {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Calculate_LegacyPrecedence(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "left to right", input: "10m+20m*2", want: "1h0m0s"},
+		{name: "left to right", input: "1h-10m*3", want: "2h30m0s"},
+		{name: "left to right", input: "1h+1h/4-10m", want: "20m0s"},
+		{name: "sign applies to first value only", input: "-1h30m", want: "-30m0s"},
+		{name: "multiple value concat in subtraction", input: "10h - 1h30m", want: "9h30m0s"},
+		{name: "parentheses", input: "10m+(20m*2)", want: "50m0s"},
+		{name: "function call", input: "ceil(7m, 5m)+1m*2", want: "22m0s"},
+		{name: "function arguments", input: "floor(10m+20m*2, 45m)", want: "45m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, dur.LegacyPrecedence).Calculate()
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got.String(), tt.want) {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Calculate_Functions(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "ceil", input: "ceil(7h52m, 15m)", want: "8h0m0s"},
+		{name: "floor", input: "floor(7h52m, 15m)", want: "7h45m0s"},
+		{name: "round down", input: "round(7h52m, 15m)", want: "7h45m0s"},
+		{name: "round up", input: "round(7h53m, 15m)", want: "8h0m0s"},
+		{name: "round halfway", input: "round(7h52m30s, 15m)", want: "8h0m0s"},
+		{name: "trunc", input: "trunc(7h59m, 15m)", want: "7h45m0s"},
+		{name: "multiple of unit", input: "ceil(8h, 15m)", want: "8h0m0s"},
+		{name: "round negative halfway", input: "round(-7h52m30s, 15m)", want: "-8h0m0s"},
+		{name: "floor negative", input: "floor(-7m, 5m)", want: "-10m0s"},
+		{name: "ceil negative", input: "ceil(-7m, 5m)", want: "-5m0s"},
+		{name: "trunc negative", input: "trunc(-7m, 5m)", want: "-5m0s"},
+		{name: "without whitespace", input: "ceil(7h52m,15m)", want: "8h0m0s"},
+		{name: "in expression", input: "2 * ceil(7m, 5m) + 1m", want: "21m0s"},
+		{name: "nested calls", input: "ceil(floor(1h7m, 5m) + 3m, 10m)", want: "1h10m0s"},
+		{name: "nested parentheses", input: "ceil(((1h + 7m) * 2), (5m * 3))", want: "2h15m0s"},
+		{name: "exact arguments", input: "round(1h/7, 1s)", want: "8m34s"},
+		{name: "fractional unit", input: "ceil(1s, 0.3s)", want: "1.2s"},
+		{name: "decimal argument", input: "ceil(1.5*1h, 1h)", want: "2h0m0s"},
+		{name: "min", input: "min(3h, 1h30m, 2h)", want: "1h30m0s"},
+		{name: "min of one", input: "min(3h)", want: "3h0m0s"},
+		{name: "max", input: "max(3h, 1h30m, -4h)", want: "3h0m0s"},
+		{name: "sum", input: "sum(1h, 2h, 3h)", want: "6h0m0s"},
+		{name: "avg", input: "avg(1h, 2h, 2h)", want: "1h40m0s"},
+		{name: "avg exact", input: "avg(1ns, 2ns) * 2", want: "3ns"},
+		{name: "median odd", input: "median(5m, 1m, 3m)", want: "3m0s"},
+		{name: "median even", input: "median(4m, 1m, 2m, 10m)", want: "3m0s"},
+		{name: "abs", input: "abs(-90m)", want: "1h30m0s"},
+		{name: "abs positive", input: "abs(90m)", want: "1h30m0s"},
+		{name: "clamp within", input: "clamp(5h, 1h, 8h)", want: "5h0m0s"},
+		{name: "clamp below", input: "clamp(-5h, 1h, 8h)", want: "1h0m0s"},
+		{name: "clamp above", input: "clamp(10h, 1h, 8h)", want: "8h0m0s"},
+		{name: "aggregate of expressions", input: "max(2 * 45m, sum(30m, 30m) + 1m)", want: "1h30m0s"},
+		{name: "number argument", input: "8h * max(1, 2, 1.5)", want: "16h0m0s"},
+		{name: "comma separates arguments", input: "8h * max(1,2)", want: "16h0m0s"},
+		{name: "comma separates arguments of sum", input: "1h * sum(1,2)", want: "3h0m0s"},
+		{name: "decimal comma outside of call", input: "1,5 * max(1h, 2h)", want: "3h0m0s"},
+		{name: "decimal comma within group", input: "2h*(1,5)", want: "3h0m0s"},
+		{name: "decimal comma within group of call", input: "max((1,5h), 1h)", want: "1h30m0s"},
+		{name: "comma separates arguments within group", input: "(max(1,2)) * 1h", want: "2h0m0s"},
+		{name: "decimal comma in group after call", input: "max(1h)*(1,5)", want: "1h30m0s"},
+		{name: "zero with durations", input: "max(0, 7h - 8h)", want: "0s"},
+		{name: "zero with durations", input: "clamp(-1h, 0, 8h) + max(0, 9h - 8h)", want: "1h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input).Calculate()
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Calculate_Days(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		opt   dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "calendar days", input: "1,5d + 1w", opt: dur.CalendarDays, want: "204h0m0s"},
+		{name: "work days", input: "3d + 2w", opt: dur.WorkDays, want: "104h0m0s"},
+		{name: "work days", input: "1,5d", opt: dur.WorkDays, want: "12h0m0s"},
+		{name: "work days", input: "0,5w", opt: dur.WorkDays, want: "20h0m0s"},
+		{name: "custom day length", input: "2d + 1w", opt: dur.DayLength(7*time.Hour+30*time.Minute, 37*time.Hour+30*time.Minute), want: "52h30m0s"},
+		{name: "custom day length", input: "0,5w", opt: dur.DayLength(7*time.Hour+30*time.Minute, 37*time.Hour+30*time.Minute), want: "18h45m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opt).Calculate()
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got.String(), tt.want) {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Calculate_Calendar(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		from  string
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "month", input: "1mo", from: "2026-01-01", want: "744h0m0s"},
+		{name: "month clamped to end of month", input: "1mo", from: "2026-01-31", want: "672h0m0s"},
+		{name: "month clamped to end of month in leap year", input: "1mo", from: "2028-01-31", want: "696h0m0s"},
+		{name: "months and weeks", input: "3mo + 2w", from: "2026-01-01", want: "2496h0m0s"},
+		{name: "year", input: "1y", from: "2026-01-01", want: "8760h0m0s"},
+		{name: "leap year", input: "1y", from: "2028-01-01", want: "8784h0m0s"},
+		{name: "year from leap day", input: "1y", from: "2028-02-29", want: "8760h0m0s"},
+		{name: "negative month", input: "-1mo", from: "2026-01-31", want: "-744h0m0s"},
+		{name: "negative month of difference", input: "0mo-1mo", from: "2026-01-31", want: "-744h0m0s"},
+		{name: "negative month of group", input: "-(1mo)", from: "2026-01-31", want: "-744h0m0s"},
+		{name: "months and minutes", input: "1mo1m", from: "2026-02-01", want: "672h1m0s"},
+		{name: "years and months", input: "1y2mo", from: "2027-12-31", want: "10200h0m0s"},
+		{name: "months added up", input: "1mo + 1mo", from: "2026-01-31", want: "1416h0m0s"},
+		{name: "months added up in groups", input: "(1mo)+(1mo)", from: "2026-01-31", want: "1416h0m0s"},
+		{name: "iso 8601 months added up", input: "P1M+P1M", from: "2026-01-31", want: "1416h0m0s"},
+		{name: "iso 8601 and unit months added up", input: "P1M + 1mo", from: "2026-01-31", want: "1416h0m0s"},
+		{name: "iso 8601 months and days", input: "P1M1D + 1mo", from: "2026-01-31", want: "1440h0m0s"},
+		{name: "months subtracted", input: "2mo - 1mo", from: "2026-01-31", want: "672h0m0s"},
+		{name: "years, months and days", input: "1y2mo3d", from: "2027-12-31", want: "10272h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, err := time.Parse("2006-01-02", tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := dur.NewCalculator(tt.input, dur.From(from)).Calculate()
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got.String(), tt.want) {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Calculate_Rounding(t *testing.T) {
+	type testCase struct {
+		input string
+		mode  dur.RoundingMode
+		want  string
+	}
+
+	tests := []testCase{
+		{input: "10ns/4", mode: dur.RoundTowardZero, want: "2ns"},
+		{input: "10ns/4", mode: dur.RoundHalfAwayFromZero, want: "3ns"},
+		{input: "10ns/4", mode: dur.RoundHalfEven, want: "2ns"},
+		{input: "10ns/4", mode: dur.RoundFloor, want: "2ns"},
+		{input: "10ns/4", mode: dur.RoundCeil, want: "3ns"},
+		{input: "-10ns/4", mode: dur.RoundTowardZero, want: "-2ns"},
+		{input: "-10ns/4", mode: dur.RoundHalfAwayFromZero, want: "-3ns"},
+		{input: "-10ns/4", mode: dur.RoundHalfEven, want: "-2ns"},
+		{input: "-10ns/4", mode: dur.RoundFloor, want: "-3ns"},
+		{input: "-10ns/4", mode: dur.RoundCeil, want: "-2ns"},
+		{input: "14ns/4", mode: dur.RoundHalfEven, want: "4ns"},
+		{input: "1ns*1.4", mode: dur.RoundHalfAwayFromZero, want: "1ns"},
+		{input: "1ns*1.6", mode: dur.RoundHalfAwayFromZero, want: "2ns"},
+		{input: "1ns*1.6", mode: dur.RoundFloor, want: "1ns"},
+		{input: "1h/11", mode: dur.RoundHalfAwayFromZero, want: "5m27.272727273s"},
+		{input: "1h/3*3", mode: dur.RoundCeil, want: "1h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, dur.Rounding(tt.mode)).Calculate()
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Calculate_Overflow(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "maximum", input: "2562047h47m16s854ms775us807ns", want: "2562047h47m16.854775807s"},
+		{name: "minimum", input: "-2562047h47m16s854ms775us807ns-1ns", want: "-2562047h47m16.854775808s"},
+		{name: "picoseconds are rounded to nanoseconds", input: "1ns + 1500ps", want: "2ns"},
+		{name: "intermediate integers do not overflow", input: "10000000000*10000000000*0,00000000000000000001h", want: "1h0m0s"},
+		{name: "saturated multiplication", input: "1562048h*2", want: "2562047h47m16.854775807s"},
+		{name: "saturated subtraction", input: "-2562047h - 2562047h", want: "-2562047h47m16.854775808s"},
+		{name: "saturated literal", input: "3000000h - 1000000h", want: "1562047h47m16.854775807s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, dur.Saturate).Calculate()
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Calculate_CalendarISO8601(t *testing.T) {
+	for _, from := range []time.Time{
+		time.Date(2027, time.December, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+	} {
+		got, err := dur.Eval("1y2mo", dur.From(from))
+		if err != nil {
+			t.Fatalf("Eval() error = %v", err)
+		}
+
+		want, err := dur.Eval("P1Y2M", dur.From(from))
+		if err != nil {
+			t.Fatalf("Eval() error = %v", err)
+		}
+
+		if got != want {
+			t.Errorf("Eval(1y2mo) = %v, want %v like P1Y2M from %v", got, want, from)
+		}
+	}
+}
+
+func TestCalculator_Calculate_CalendarOverflow(t *testing.T) {
+	from := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := dur.NewCalculator("300y", dur.From(from)).Calculate()
+
+	var calcErr *dur.Error
+	if !errors.As(err, &calcErr) || calcErr.Kind != dur.KindOverflow {
+		t.Errorf("Calculate() error = %v, want overflow", err)
+	}
+}
+
+func TestCalculator_Evaluate_BigPrecision(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "within range", input: "1h30m + 15m", want: "1h45m0s"},
+		{name: "beyond range", input: "3000000h * 2", want: "6000000h0m0s"},
+		{name: "negative beyond range", input: "-3000000h - 1s", want: "-3000000h0m1s"},
+		{name: "sum of centuries", input: "500 * 365d + 1ns", want: "4380000h0m0.000000001s"},
+		{name: "ratio beyond range", input: "6000000h / 3000000h", want: "2"},
+		{name: "picoseconds", input: "1ps", want: "1ps"},
+		{name: "picoseconds with nanoseconds", input: "1ns + 500ps", want: "1.5ns"},
+		{name: "picoseconds with seconds", input: "1s + 1ps", want: "1.000000000001s"},
+		{name: "microseconds", input: "1500ns + 1ps", want: "1.500001µs"},
+		{name: "fractions of picoseconds are rounded", input: "1h/11", want: "5m27.272727272727s"},
+		{name: "sub-picosecond result", input: "1ps/3", want: "0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, dur.BigPrecision).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_BigPrecisionResult(t *testing.T) {
+	r, err := dur.NewCalculator("3000000h + 1ps", dur.BigPrecision).Evaluate()
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if _, ok := r.Duration(); ok {
+		t.Errorf("Duration() ok = true, want false for a result beyond range")
+	}
+
+	ns, ok := r.Nanoseconds()
+	if want := "10800000000000000000001/1000"; !ok || ns.String() != want {
+		t.Errorf("Nanoseconds() = %v, %v, want %v", ns, ok, want)
+	}
+
+	_, err = dur.NewCalculator("3000000h", dur.BigPrecision).Calculate()
+
+	var calcErr *dur.Error
+	if !errors.As(err, &calcErr) || calcErr.Kind != dur.KindOverflow {
+		t.Errorf("Calculate() error = %v, want overflow", err)
+	}
+
+	d, err := dur.NewCalculator("1h + 1500ps", dur.BigPrecision).Calculate()
+	if want := time.Hour + time.Nanosecond; err != nil || d != want {
+		t.Errorf("Calculate() = %v, %v, want %v", d, err, want)
+	}
+}
+
+func TestCalculator_Evaluate(t *testing.T) {
+	type testCase struct {
+		name       string
+		input      string
+		want       string
+		isDuration bool
+	}
+
+	tests := []testCase{
+		{name: "duration", input: "1h+1h", want: "2h0m0s", isDuration: true},
+		{name: "ratio", input: "1h/15m", want: "4"},
+		{name: "ratio", input: "24h/45m", want: "32"},
+		{name: "ratio", input: "1h/7m", want: "8.571428571"},
+		{name: "ratio", input: "-30m/1h", want: "-0.5"},
+		{name: "ratio of ratios", input: "(1h/15m)/(1h/30m)", want: "2"},
+		{name: "ratio feeds multiplication", input: "(8h/45m)*5m", want: "53m20s", isDuration: true},
+		{name: "ratio feeds division", input: "1h/(1h/15m)", want: "15m0s", isDuration: true},
+		{name: "integer", input: "2*3", want: "6"},
+		{name: "integer division", input: "7/2", want: "3.5"},
+		{name: "integer division", input: "6/2", want: "3"},
+		{name: "decimal", input: "1.5", want: "1.5"},
+		{name: "percent", input: "20%", want: "20%"},
+		{name: "percent", input: "20% - 7,5%", want: "12.5%"},
+		{name: "percent of number", input: "200 + 10%", want: "220"},
+		{name: "sum of numbers", input: "sum(1, 2, 3)", want: "6"},
+		{name: "avg of numbers", input: "avg(1, 2)", want: "1.5"},
+		{name: "median of numbers", input: "median(3, 1, 2, 4)", want: "2.5"},
+		{name: "abs of number", input: "abs(-1.5)", want: "1.5"},
+		{name: "clamp of number", input: "clamp(12, 0, 10)", want: "10"},
+		{name: "avg of durations", input: "avg(1h, 2h)", want: "1h30m0s", isDuration: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want || got.IsDuration() != tt.isDuration {
+				t.Errorf("Evaluate() = %v (duration: %v), want %v (duration: %v)", got, got.IsDuration(), tt.want, tt.isDuration)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_Statements(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "variables", input: "rate = 45m; sessions = 7; rate*sessions + 10m", want: "5h25m0s"},
+		{name: "newlines", input: "rate = 45m\nsessions = 7\r\nrate*sessions + 10m\n", want: "5h25m0s"},
+		{name: "previous statement", input: "8h*5; _ - 2h30m", want: "37h30m0s"},
+		{name: "numbered statements", input: "1h; 2h; _1 + _2 * 2", want: "5h0m0s"},
+		{name: "reassignment", input: "a = 1h; a = a * 2; a + 1m", want: "2h1m0s"},
+		{name: "empty statements", input: ";; 1h ;\n\n", want: "1h0m0s"},
+		{name: "assignment is last statement", input: "a = 90m", want: "1h30m0s"},
+		{name: "number variable", input: "n = 3; n * 2", want: "6"},
+		{name: "variable in function call", input: "base = 7h52m; ceil(base, 15m)", want: "8h0m0s"},
+		{name: "variables follow each other", input: "a = 1h; b = 30m; a b", want: "1h30m0s"},
+		{name: "variable with percent", input: "fee = 10%; 1h + fee", want: "1h6m0s"},
+		{name: "legacy precedence", input: "a = 10m; a+20m*2", opts: []dur.Option{dur.LegacyPrecedence}, want: "1h0m0s"},
+		{name: "divmod of last statement", input: "slot = 25m; 3h/slot", opts: []dur.Option{dur.DivMod}, want: "7 rem 5m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_Def(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "overtime", input: "def overtime(x) = max(0, x - 8h); overtime(9h30m)", want: "1h30m0s"},
+		{name: "typed parameter", input: "def overtime(x: duration) = max(0, x - 8h); overtime(7h)", want: "0s"},
+		{name: "buffered estimate", input: "def buffered(x) = x*1.2 + 30m\nbuffered(2h)", want: "2h54m0s"},
+		{name: "multiple parameters", input: "def bill(x: duration, step: duration) = ceil(x, step); bill(7h52m, 15m)", want: "8h0m0s"},
+		{name: "number parameter", input: "def twice(n: number) = n * 2; twice(1.5)", want: "3"},
+		{name: "percentage parameter", input: "def fee(x, p: percentage) = x + p; fee(1h, 10%)", want: "1h6m0s"},
+		{name: "without parameters", input: "def workday() = 8h; workday() * 5", want: "40h0m0s"},
+		{name: "calls defined function", input: "def a(x) = x*2; def b(x) = a(x) + 1m; b(1h)", want: "2h1m0s"},
+		{name: "defined later", input: "def b(x) = a(x) + 1m; def a(x) = x*2; b(1h)", want: "2h1m0s"},
+		{name: "global variable", input: "rate = 45m; def cost(n: number) = rate*n; cost(3)", want: "2h15m0s"},
+		{name: "parameter shadows variable", input: "x = 1h; def f(x) = x*2; f(2h) + x", want: "5h0m0s"},
+		{name: "redefinition", input: "def f(x) = x; def f(x) = x*2; f(1h)", want: "2h0m0s"},
+		{name: "only definitions", input: "def f(x) = x", want: "0s"},
+		{name: "previous statement skips definition", input: "1h; def f(x) = x; _ + f(1h)", want: "2h0m0s"},
+		{name: "nesting within limit", input: "def a(x) = b(x); def b(x) = c(x); def c(x) = x; a(1h)", opts: []dur.Option{dur.RecursionLimit(3)}, want: "1h0m0s"},
+		{name: "legacy precedence", input: "def f(x) = x+20m*2; f(10m)", opts: []dur.Option{dur.LegacyPrecedence}, want: "1h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_DefErrors(t *testing.T) {
+	type testCase struct {
+		name   string
+		input  string
+		opts   []dur.Option
+		want   string
+		kind   dur.ErrorKind
+		offset int
+	}
+
+	tests := []testCase{
+		{name: "recursion", input: "def f(x) = f(x); f(1h)", want: "recursion limit of 100 exceeded in 'f'", kind: dur.KindRecursionLimit, offset: 11},
+		{name: "nesting beyond limit", input: "def a(x) = b(x); def b(x) = c(x); def c(x) = x; a(1h)", opts: []dur.Option{dur.RecursionLimit(2)}, want: "recursion limit of 2 exceeded in 'c'", kind: dur.KindRecursionLimit, offset: 28},
+		{name: "parameter type", input: "def overtime(x: duration) = x; overtime(2)", want: "overtime requires a duration as argument 1 (x), got number", kind: dur.KindInvalidOperation, offset: 40},
+		{name: "arity", input: "def f(x) = x; f(1h, 2h)", want: "f expects 1 argument, got 2", kind: dur.KindInvalidOperation, offset: 14},
+		{name: "redefine built-in", input: "def min(x) = x", want: "cannot redefine built-in function 'min'", kind: dur.KindInvalidOperation, offset: 4},
+		{name: "unknown type", input: "def f(x: text) = x", want: "unknown type 'text', want duration, number, percentage, list, timestamp or clock", kind: dur.KindUnexpectedToken, offset: 9},
+		{name: "duplicate parameter", input: "def f(x, x) = x", want: "duplicate parameter 'x'", kind: dur.KindUnexpectedToken, offset: 9},
+		{name: "missing assignment", input: "def f(x) x", want: "unexpected token 'IDENT'", kind: dur.KindUnexpectedToken, offset: 9},
+		{name: "missing body", input: "def f(x) =", want: "unexpected token 'EOF'", kind: dur.KindUnexpectedToken, offset: 10},
+		{name: "missing name", input: "def (x) = x", want: "unexpected token 'PAREN_OPEN'", kind: dur.KindUnexpectedToken, offset: 4},
+		{name: "trailing comma", input: "def f(x,) = x", want: "unexpected token 'COMMA'", kind: dur.KindUnexpectedToken, offset: 7},
+		{name: "definition in expression", input: "1h + def f(x) = x", want: "unexpected token 'DEF'", kind: dur.KindUnexpectedToken, offset: 5},
+		{name: "undefined variable in body", input: "def f(x) = x + y; f(1h)", want: "undefined variable 'y'", kind: dur.KindUndefined, offset: 15},
+		{name: "no access to caller scope", input: "def g(x) = y; def f(y) = g(1h); f(2h)", want: "undefined variable 'y'", kind: dur.KindUndefined, offset: 11},
+		{name: "local function", input: "def f(x) = x; g(1h)", want: "unknown function 'g'", kind: dur.KindUndefined, offset: 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+
+			var calcErr *dur.Error
+			if !errors.As(err, &calcErr) {
+				t.Fatalf("Evaluate() error = %v, want *dur.Error", err)
+			}
+
+			if calcErr.Message != tt.want || calcErr.Kind != tt.kind || calcErr.Offset != tt.offset {
+				t.Errorf("Evaluate() error = %v (%v at %v), want %v (%v at %v)", calcErr, calcErr.Kind, calcErr.Offset, tt.want, tt.kind, tt.offset)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_Lists(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "literal", input: "[1h, 2h30m, 45m]", want: "[1h0m0s, 2h30m0s, 45m0s]"},
+		{name: "empty", input: "[]", want: "[]"},
+		{name: "expressions as elements", input: "[1h + 30m, 2 * 15m, (1h)]", want: "[1h30m0s, 30m0s, 1h0m0s]"},
+		{name: "multiply with scalar", input: "[1h,2h]*2", want: "[2h0m0s, 4h0m0s]"},
+		{name: "scalar first", input: "2*[1h,2h]", want: "[2h0m0s, 4h0m0s]"},
+		{name: "add lists", input: "[1h,2h]+[30m,15m]", want: "[1h30m0s, 2h15m0s]"},
+		{name: "add duration", input: "[1h,2h] + 15m", want: "[1h15m0s, 2h15m0s]"},
+		{name: "subtract from duration", input: "8h - [7h, 9h]", want: "[1h0m0s, -1h0m0s]"},
+		{name: "divide lists", input: "[1h, 3h] / [30m, 1h]", want: "[2, 3]"},
+		{name: "modulo", input: "[70m, 130m] mod 1h", want: "[10m0s, 10m0s]"},
+		{name: "percent", input: "[1h, 2h] + 10%", want: "[1h6m0s, 2h12m0s]"},
+		{name: "negate", input: "-[1h, -2h]", want: "[-1h0m0s, 2h0m0s]"},
+		{name: "precedence", input: "[1h, 2h] + [1h, 2h] * 2", want: "[3h0m0s, 6h0m0s]"},
+		{name: "numbers", input: "[1, 2] * 3", want: "[3, 6]"},
+		{name: "nested", input: "[[1h], [2h, 3h]] * 2", want: "[[2h0m0s], [4h0m0s, 6h0m0s]]"},
+		{name: "sum", input: "sum([1h, 2h30m, 45m])", want: "4h15m0s"},
+		{name: "avg", input: "avg([1h, 2h] * 2)", want: "3h0m0s"},
+		{name: "max of lists and values", input: "max([1h, 2h], 90m, [3h])", want: "3h0m0s"},
+		{name: "median", input: "median([3m, 1m, 2m])", want: "2m0s"},
+		{name: "count", input: "count([1h, 2h], 3h)", want: "3"},
+		{name: "count empty", input: "count([])", want: "0"},
+		{name: "ceil each", input: "ceil([7h52m, 1h1m], 15m)", want: "[8h0m0s, 1h15m0s]"},
+		{name: "abs each", input: "abs([-1h, 2h])", want: "[1h0m0s, 2h0m0s]"},
+		{name: "clamp each", input: "clamp([-1h, 2h, 9h], 0, 8h)", want: "[0s, 2h0m0s, 8h0m0s]"},
+		{name: "variable", input: "week = [8h, 7h30m, 8h15m]; sum(week) - 5 * 8h + week * 0", want: "[-16h15m0s, -16h15m0s, -16h15m0s]"},
+		{name: "defined function", input: "def overtime(x) = max(0, x - 8h); sum(overtime([9h, 7h, 8h30m]))", want: "1h0m0s"},
+		{name: "list parameter", input: "def total(l: list) = sum(l); total([1h, 2h])", want: "3h0m0s"},
+		{name: "legacy precedence", input: "[10m, 20m]+20m*2", opts: []dur.Option{dur.LegacyPrecedence}, want: "[1h0m0s, 1h20m0s]"},
+		{name: "rounding of elements", input: "[1ns, 2ns] / 3", opts: []dur.Option{dur.Rounding(dur.RoundHalfEven)}, want: "[0s, 1ns]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_ListOfIntegers(t *testing.T) {
+	r, err := dur.Evaluate("[1,2]")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if l, ok := r.List(); !ok || len(l) != 2 || r.String() != "[1, 2]" {
+		t.Errorf("Evaluate() = %v, want the two elements [1, 2]", r)
+	}
+}
+
+func TestCalculator_Evaluate_ListResult(t *testing.T) {
+	r, err := dur.NewCalculator("[1h, 1h/15m]").Evaluate()
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if r.IsDuration() {
+		t.Errorf("IsDuration() = true, want false for a list")
+	}
+
+	elems, ok := r.List()
+	if !ok || len(elems) != 2 {
+		t.Fatalf("List() = %v, %v, want 2 elements", elems, ok)
+	}
+
+	if d, ok := elems[0].Duration(); !ok || d != time.Hour {
+		t.Errorf("List()[0] = %v, want %v", elems[0], time.Hour)
+	}
+
+	if n, ok := elems[1].Number(); !ok || n.RatString() != "4" {
+		t.Errorf("List()[1] = %v, want 4", elems[1])
+	}
+
+	if _, err := dur.NewCalculator("[1h]").Calculate(); err == nil {
+		t.Errorf("Calculate() error = nil, want error for a list")
+	}
+}
+
+func TestCalculator_Evaluate_Timestamps(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "timestamp", input: "2026-10-18T09:12", want: "2026-10-18T09:12:00Z"},
+		{name: "date", input: "2026-10-18", want: "2026-10-18T00:00:00Z"},
+		{name: "plus duration", input: "2026-10-18T09:12 + 3h47m", want: "2026-10-18T12:59:00Z"},
+		{name: "date and time", input: "2026-10-18 09:12 + 3h47m", want: "2026-10-18T12:59:00Z"},
+		{name: "seconds", input: "2026-10-18 09:12:30 + 30s", want: "2026-10-18T09:13:00Z"},
+		{name: "zone", input: "2026-10-18T09:12:30+02:00 + 1h", want: "2026-10-18T10:12:30+02:00"},
+		{name: "past midnight", input: "2026-10-18T23:30:00Z + 45m", want: "2026-10-19T00:15:00Z"},
+		{name: "minus duration", input: "2026-10-18 - 1d", want: "2026-10-17T00:00:00Z"},
+		{name: "duration first", input: "30m + 2026-10-18T09:00", want: "2026-10-18T09:30:00Z"},
+		{name: "between timestamps", input: "2026-10-18T12:00:00Z - 2026-10-18T09:12:00Z", want: "2h48m0s"},
+		{name: "between zones", input: "2026-10-18T12:00:00Z - 2026-10-18T12:00:00+02:00", want: "2h0m0s"},
+		{name: "between dates", input: "2026-03-01 - 2026-02-01", want: "672h0m0s"},
+		{name: "fractional seconds", input: "2026-10-18T09:12:00.5Z + 0,25s", want: "2026-10-18T09:12:00.75Z"},
+		{name: "exact", input: "2026-10-18T09:00 + 1h/3", want: "2026-10-18T09:20:00Z"},
+		{name: "rounding", input: "2026-10-18 + 1ns/2", opts: []dur.Option{dur.Rounding(dur.RoundHalfAwayFromZero)}, want: "2026-10-18T00:00:00.000000001Z"},
+		{name: "ratio of timestamps", input: "end = 2026-10-18T17:00; start = 2026-10-18T09:00; (end - start) / 1h", want: "8"},
+		{name: "list", input: "[2026-10-18T09:00, 2026-10-18T10:00] + 30m", want: "[2026-10-18T09:30:00Z, 2026-10-18T10:30:00Z]"},
+		{name: "beyond range of a duration", input: "2500-01-01 - 1900-01-01", opts: []dur.Option{dur.BigPrecision}, want: "5259504h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_TimestampResult(t *testing.T) {
+	r, err := dur.NewCalculator("2026-10-18T09:12+02:00 + 3h47m").Evaluate()
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	want := time.Date(2026, time.October, 18, 10, 59, 0, 0, time.UTC)
+	if got, ok := r.Time(); !ok || !got.Equal(want) || !r.IsTime() {
+		t.Errorf("Time() = %v, %v, want %v", got, ok, want)
+	}
+
+	if r.IsDuration() {
+		t.Errorf("IsDuration() = true, want false for a timestamp")
+	}
+}
+
+func TestCalculator_Evaluate_Clock(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "range", input: "09:15-17:45", want: "8h30m0s"},
+		{name: "dots", input: "09:15..17:45", want: "8h30m0s"},
+		{name: "spaces", input: "09:15 - 17:45", want: "8h30m0s"},
+		{name: "sum of ranges", input: "08:30-12:00 12:45-17:15", want: "8h0m0s"},
+		{name: "sum of ranges with plus", input: "08:30-12:00 + 12:45-17:15", want: "8h0m0s"},
+		{name: "past midnight", input: "22:00-06:00", want: "8h0m0s"},
+		{name: "end of day", input: "00:00-24:00", want: "24h0m0s"},
+		{name: "single digit hour", input: "9:00-9:30", want: "30m0s"},
+		{name: "seconds", input: "09:00:30-10:00", want: "59m30s"},
+		{name: "range binds tightest", input: "2*09:00-12:00", want: "6h0m0s"},
+		{name: "minus duration after range", input: "08:00-17:00 - 30m", want: "8h30m0s"},
+		{name: "list of ranges", input: "sum([08:30-12:00, 12:45-17:15])", want: "8h0m0s"},
+		{name: "clock time", input: "17:45", want: "17:45"},
+		{name: "plus duration", input: "17:45 + 30m", want: "18:15"},
+		{name: "wraps past midnight", input: "23:30 + 1h", want: "00:30"},
+		{name: "minus duration", input: "00:15 - 30m", want: "23:45"},
+		{name: "duration first", input: "30m + 09:00", want: "09:30"},
+		{name: "fraction of seconds", input: "09:00 + 1,5s", want: "09:00:01.5"},
+		{name: "range of variables", input: "a = 22:00; b = 06:00; a - b", want: "8h0m0s"},
+		{name: "range of variable and literal", input: "t = 09:15; t - 17:45", want: "8h30m0s"},
+		{name: "typed parameter", input: "def shift(c: clock) = c + 8h; shift(22:00)", want: "06:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_ClockRangeForms(t *testing.T) {
+	for _, literal := range []string{"17:45 - 09:15", "09:15-17:45", "22:00-06:00", "06:00..22:00"} {
+		want, err := dur.Evaluate(literal)
+		if err != nil {
+			t.Fatalf("Evaluate(%v) error = %v", literal, err)
+		}
+
+		var (
+			from, to = literal[:5], literal[len(literal)-5:]
+			forms    = []string{
+				"(" + from + ") - (" + to + ")",
+				"a = " + from + "; b = " + to + "; a - b",
+				"a = " + from + "; a - " + to,
+			}
+		)
+
+		for _, form := range forms {
+			got, err := dur.Evaluate(form)
+			if err != nil {
+				t.Fatalf("Evaluate(%v) error = %v", form, err)
+			}
+
+			if got.String() != want.String() {
+				t.Errorf("Evaluate(%v) = %v, want %v like %v", form, got, want, literal)
+			}
+		}
+	}
+}
+
+func TestCalculator_Evaluate_ClockResult(t *testing.T) {
+	r, err := dur.NewCalculator("17:45").Evaluate()
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if got, ok := r.Clock(); !ok || got != 17*time.Hour+45*time.Minute {
+		t.Errorf("Clock() = %v, %v, want 17h45m0s", got, ok)
+	}
+
+	if r.IsDuration() {
+		t.Errorf("IsDuration() = true, want false for a clock time")
+	}
+}
+
+func TestCalculator_Evaluate_Colon(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "days", input: "2.04:30:00", want: "52h30m0s"},
+		{name: "fraction of seconds", input: "1:23:45.5", want: "1h23m45.5s"},
+		{name: "days and fraction", input: "1.00:00:00.000000001 - 24h", want: "1ns"},
+		{name: "hours beyond a day", input: "100:00:00", want: "100h0m0s"},
+		{name: "with units", input: "2.04:30:00 + 30m", want: "53h0m0s"},
+		{name: "work days do not apply", input: "1.00:00:00", opts: []dur.Option{dur.WorkDays}, want: "24h0m0s"},
+		{name: "hours and minutes", input: "1:23", opts: []dur.Option{dur.ColonDurations}, want: "1h23m0s"},
+		{name: "hours, minutes and seconds", input: "1:23:45 + 0:40", opts: []dur.Option{dur.ColonDurations}, want: "2h3m45s"},
+		{name: "no ranges", input: "1:30-0:45", opts: []dur.Option{dur.ColonDurations}, want: "45m0s"},
+		{name: "clock by default", input: "1:30", want: "01:30"},
+		{name: "list", input: "[1:30, 0:15]*2", opts: []dur.Option{dur.ColonDurations}, want: "[3h0m0s, 30m0s]"},
+		{name: "colon output", input: "1h30m", opts: []dur.Option{dur.Output(dur.FormatColon)}, want: "1:30:00"},
+		{name: "colon output of days", input: "2.04:30:00", opts: []dur.Option{dur.Output(dur.FormatColon)}, want: "2.04:30:00"},
+		{name: "colon output of fraction", input: "1:23:45.5", opts: []dur.Option{dur.Output(dur.FormatColon)}, want: "1:23:45.5"},
+		{name: "colon output of negative", input: "-90s", opts: []dur.Option{dur.Output(dur.FormatColon)}, want: "-0:01:30"},
+		{name: "colon output of zero", input: "0s", opts: []dur.Option{dur.Output(dur.FormatColon)}, want: "0:00:00"},
+		{name: "colon output of list", input: "[90m, 25h]", opts: []dur.Option{dur.Output(dur.FormatColon)}, want: "[1:30:00, 1.01:00:00]"},
+		{name: "colon output of number", input: "3h/2h", opts: []dur.Option{dur.Output(dur.FormatColon)}, want: "1.5"},
+		{name: "colon output of remainder", input: "3h/25m", opts: []dur.Option{dur.Output(dur.FormatColon), dur.DivMod}, want: "7 rem 0:05:00"},
+		{name: "colon output of picoseconds", input: "1ps", opts: []dur.Option{dur.Output(dur.FormatColon), dur.BigPrecision}, want: "0:00:00.000000000001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_ISO8601(t *testing.T) {
+	anchor := dur.From(time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC))
+
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "hours and minutes", input: "PT1H30M", want: "1h30m0s"},
+		{name: "days and hours", input: "P3DT4H", want: "76h0m0s"},
+		{name: "fraction of seconds", input: "PT0.5S", want: "500ms"},
+		{name: "decimal comma", input: "PT0,5S", want: "500ms"},
+		{name: "weeks", input: "P2W", want: "336h0m0s"},
+		{name: "fraction of days", input: "P1.5D", want: "36h0m0s"},
+		{name: "work days do not apply", input: "P1D", opts: []dur.Option{dur.WorkDays}, want: "24h0m0s"},
+		{name: "negative", input: "-PT1H", want: "-1h0m0s"},
+		{name: "calculation", input: "PT8H - PT30M + 15m", want: "7h45m0s"},
+		{name: "function", input: "ceil(PT1H10M, PT15M)", want: "1h15m0s"},
+		{name: "months with anchor", input: "P1M", opts: []dur.Option{anchor}, want: "672h0m0s"},
+		{name: "years and months with anchor", input: "P1Y1M", opts: []dur.Option{anchor}, want: "9432h0m0s"},
+		{name: "months and time with anchor", input: "P1MT1H", opts: []dur.Option{anchor}, want: "673h0m0s"},
+		{name: "identifier", input: "PTO = 8h; PTO*2", want: "16h0m0s"},
+		{name: "iso8601 output", input: "1h30m", opts: []dur.Option{dur.Output(dur.FormatISO8601)}, want: "PT1H30M"},
+		{name: "iso8601 output of days", input: "3d4h", opts: []dur.Option{dur.Output(dur.FormatISO8601)}, want: "PT76H"},
+		{name: "iso8601 output of fraction", input: "1m0.5s", opts: []dur.Option{dur.Output(dur.FormatISO8601)}, want: "PT1M0.5S"},
+		{name: "iso8601 output of negative", input: "-90s", opts: []dur.Option{dur.Output(dur.FormatISO8601)}, want: "-PT1M30S"},
+		{name: "iso8601 output of zero", input: "0s", opts: []dur.Option{dur.Output(dur.FormatISO8601)}, want: "PT0S"},
+		{name: "iso8601 output of picoseconds", input: "1ps", opts: []dur.Option{dur.Output(dur.FormatISO8601), dur.BigPrecision}, want: "PT0.000000000001S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_ISO8601RoundTrip(t *testing.T) {
+	durations := []time.Duration{
+		0, time.Nanosecond, 500 * time.Millisecond, 90 * time.Second, time.Hour, 76*time.Hour + 59*time.Second,
+		-(1*time.Hour + 1*time.Nanosecond), time.Duration(math.MaxInt64), time.Duration(math.MinInt64 + 1),
+	}
+
+	for _, d := range durations {
+		t.Run(d.String(), func(t *testing.T) {
+			iso, err := dur.Evaluate(d.String(), dur.Output(dur.FormatISO8601))
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			got, err := dur.Eval(iso.String())
+			if err != nil {
+				t.Fatalf("Eval(%v) error = %v", iso, err)
+			}
+
+			if got != d {
+				t.Errorf("Eval(%v) = %v, want %v", iso, got, d)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_Format(t *testing.T) {
+	var (
+		decimal = dur.Output(dur.FormatDecimal)
+		compact = dur.Output(dur.FormatCompact)
+		integer = dur.Output(dur.FormatInt)
+	)
+
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "decimal hours", input: "8h15m", opts: []dur.Option{decimal, dur.OutputUnit("h"), dur.Decimals(2)}, want: "8.25h"},
+		{name: "decimal rounded", input: "8h20m", opts: []dur.Option{decimal, dur.OutputUnit("h"), dur.Decimals(2)}, want: "8.33h"},
+		{name: "decimal rounded half away", input: "-15m", opts: []dur.Option{decimal, dur.OutputUnit("h"), dur.Decimals(1)}, want: "-0.3h"},
+		{name: "decimal padded", input: "8h", opts: []dur.Option{decimal, dur.OutputUnit("h"), dur.Decimals(2)}, want: "8.00h"},
+		{name: "decimal without fraction", input: "8h29m", opts: []dur.Option{decimal, dur.OutputUnit("h"), dur.Decimals(0)}, want: "8h"},
+		{name: "decimal as needed", input: "8h15m", opts: []dur.Option{decimal, dur.OutputUnit("m")}, want: "495m"},
+		{name: "decimal largest unit", input: "90m", opts: []dur.Option{decimal}, want: "1.5h"},
+		{name: "decimal largest unit below a second", input: "1500us", opts: []dur.Option{decimal}, want: "1.5ms"},
+		{name: "decimal up to days", input: "36h", opts: []dur.Option{decimal, dur.MaxUnit("d")}, want: "1.5d"},
+		{name: "decimal up to minutes", input: "36h", opts: []dur.Option{decimal, dur.MaxUnit("m")}, want: "2160m"},
+		{name: "decimal work days", input: "12h", opts: []dur.Option{decimal, dur.OutputUnit("d"), dur.WorkDays}, want: "1.5d"},
+		{name: "decimal zero", input: "0s", opts: []dur.Option{decimal}, want: "0s"},
+		{name: "compact", input: "12h", opts: []dur.Option{compact}, want: "12h"},
+		{name: "compact leaves out zero values", input: "1h0m30s", opts: []dur.Option{compact}, want: "1h30s"},
+		{name: "compact fraction", input: "-1m0.5s", opts: []dur.Option{compact}, want: "-1m0.5s"},
+		{name: "compact below a second", input: "250ms", opts: []dur.Option{compact}, want: "250ms"},
+		{name: "compact zero", input: "0s", opts: []dur.Option{compact}, want: "0s"},
+		{name: "compact up to days", input: "36h", opts: []dur.Option{compact, dur.MaxUnit("d")}, want: "1d12h"},
+		{name: "compact up to weeks", input: "400h1ms", opts: []dur.Option{compact, dur.MaxUnit("w")}, want: "2w2d16h0.001s"},
+		{name: "compact up to seconds", input: "2h", opts: []dur.Option{compact, dur.MaxUnit("s")}, want: "7200s"},
+		{name: "compact work weeks", input: "50h", opts: []dur.Option{compact, dur.MaxUnit("w"), dur.WorkDays}, want: "1w1d2h"},
+		{name: "compact beyond range", input: "1000*365d + 1ps", opts: []dur.Option{compact, dur.BigPrecision}, want: "8760000h0.000000000001s"},
+		{name: "compact list", input: "[90m, 0s]", opts: []dur.Option{compact}, want: "[1h30m, 0s]"},
+		{name: "int seconds", input: "12h", opts: []dur.Option{integer, dur.OutputUnit("s")}, want: "43200"},
+		{name: "int seconds by default", input: "90m", opts: []dur.Option{integer}, want: "5400"},
+		{name: "int truncates", input: "-90s", opts: []dur.Option{integer, dur.OutputUnit("m")}, want: "-1"},
+		{name: "int nanoseconds", input: "1h", opts: []dur.Option{integer, dur.OutputUnit("ns")}, want: "3600000000000"},
+		{name: "numbers are not formatted", input: "3h/2h", opts: []dur.Option{integer}, want: "1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_FormatErrors(t *testing.T) {
+	for _, opt := range []dur.Option{dur.OutputUnit("x"), dur.MaxUnit("mo"), dur.Language("xx")} {
+		_, err := dur.NewCalculator("1h", dur.Output(dur.FormatDecimal), opt).Evaluate()
+
+		var calcErr *dur.Error
+		if !errors.As(err, &calcErr) || calcErr.Kind != dur.KindInvalidValue {
+			t.Errorf("Evaluate() error = %v, want %v", err, dur.KindInvalidValue)
+		}
+	}
+}
+
+func TestCalculator_Evaluate_Human(t *testing.T) {
+	dur.RegisterLocale("test", dur.Locale{
+		Units: map[string]dur.UnitNames{"h": {One: "hr", Other: "hrs"}, "m": {One: "min", Other: "mins"}},
+		About: "~",
+	})
+
+	var human = dur.Output(dur.FormatHuman)
+
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "hours and minutes", input: "2h30m", opts: []dur.Option{human}, want: "2 hours 30 minutes"},
+		{name: "singular", input: "1h1m1s", opts: []dur.Option{human}, want: "1 hour 1 minute 1 second"},
+		{name: "below a second", input: "1500ms", opts: []dur.Option{human}, want: "1 second 500 milliseconds"},
+		{name: "zero", input: "0s", opts: []dur.Option{human}, want: "0 seconds"},
+		{name: "negative", input: "-90m", opts: []dur.Option{human}, want: "-1 hour 30 minutes"},
+		{name: "up to hours by default", input: "49h", opts: []dur.Option{human}, want: "49 hours"},
+		{name: "up to days", input: "49h", opts: []dur.Option{human, dur.MaxUnit("d")}, want: "2 days 1 hour"},
+		{name: "german", input: "2h30m", opts: []dur.Option{human, dur.Language("de")}, want: "2 Stunden 30 Minuten"},
+		{name: "german singular", input: "1d1h1m", opts: []dur.Option{human, dur.Language("de"), dur.MaxUnit("d")}, want: "1 Tag 1 Stunde 1 Minute"},
+		{name: "granularity", input: "2h29m40s", opts: []dur.Option{human, dur.Granularity(time.Minute)}, want: "2 hours 30 minutes"},
+		{name: "granularity halfway", input: "7m30s", opts: []dur.Option{human, dur.Granularity(15 * time.Minute)}, want: "15 minutes"},
+		{name: "granularity to zero", input: "20s", opts: []dur.Option{human, dur.Granularity(time.Minute)}, want: "0 seconds"},
+		{name: "approximate", input: "2h40m", opts: []dur.Option{human, dur.Approximate}, want: "about 3 hours"},
+		{name: "approximate down", input: "2h29m", opts: []dur.Option{human, dur.Approximate}, want: "about 2 hours"},
+		{name: "approximate exact", input: "3h", opts: []dur.Option{human, dur.Approximate}, want: "3 hours"},
+		{name: "approximate negative", input: "-2h40m", opts: []dur.Option{human, dur.Approximate}, want: "about -3 hours"},
+		{name: "approximate german", input: "2h40m", opts: []dur.Option{human, dur.Approximate, dur.Language("de")}, want: "etwa 3 Stunden"},
+		{name: "registered locale", input: "2h40m", opts: []dur.Option{human, dur.Approximate, dur.Language("test")}, want: "~ 3 hrs"},
+		{name: "list", input: "[1h, 2m]", opts: []dur.Option{human}, want: "[1 hour, 2 minutes]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_UnitAliases(t *testing.T) {
+	dur.RegisterLocale("alias", dur.Locale{Aliases: map[string]string{"timmar": "h"}})
+
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "short form", input: "90 min", want: "1h30m0s"},
+		{name: "plural short form", input: "2 hrs", want: "2h0m0s"},
+		{name: "long forms", input: "1 hour 30 minutes", want: "1h30m0s"},
+		{name: "without whitespace", input: "1hour30minutes", want: "1h30m0s"},
+		{name: "seconds", input: "3 secs", want: "3s"},
+		{name: "upper case", input: "1H", want: "1h0m0s"},
+		{name: "upper case milliseconds", input: "1MS", want: "1ms"},
+		{name: "mixed case", input: "2 Hours", want: "2h0m0s"},
+		{name: "german", input: "5 Minuten", want: "5m0s"},
+		{name: "german abbreviation", input: "1,5 Stunden + 2 Std", want: "3h30m0s"},
+		{name: "german days", input: "1 Tag", want: "24h0m0s"},
+		{name: "tab", input: "90\tmin", want: "1h30m0s"},
+		{name: "base unit with whitespace", input: "2 h", want: "2h0m0s"},
+		{name: "work days", input: "2 days", opts: []dur.Option{dur.WorkDays}, want: "16h0m0s"},
+		{name: "months", input: "1 month", opts: []dur.Option{dur.From(time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC))}, want: "672h0m0s"},
+		{name: "german years", input: "1 Jahr", opts: []dur.Option{dur.From(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))}, want: "8760h0m0s"},
+		{name: "modulo", input: "2h mod 25 min", want: "20m0s"},
+		{name: "registered alias", input: "2 timmar", want: "2h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, tt.opts...).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculator_Evaluate_DivMod(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  string
+	}
+
+	tests := []testCase{
+		{name: "durations", input: "3h/25m", want: "7 rem 5m0s"},
+		{name: "durations in parentheses", input: "((3h+10m)/25m)", want: "7 rem 15m0s"},
+		{name: "duration by integer", input: "1h/11", want: "5m27.272727272s rem 8ns"},
+		{name: "integers", input: "7/2", want: "3 rem 1"},
+		{name: "negative", input: "-1h/25m", want: "-2 rem -10m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dur.NewCalculator(tt.input, dur.DivMod).Evaluate()
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := dur.NewCalculator("3h+1h", dur.DivMod).Evaluate(); err == nil {
+		t.Errorf("Evaluate() expected error for divmod without division")
+	}
+}
+
+func TestCalculator_Calculate_Errors(t *testing.T) {
+	type testCase struct {
+		name   string
+		input  string
+		want   string
+		kind   dur.ErrorKind
+		offset int
+	}
+
+	tests := []testCase{
+		{name: "unexpected character", input: "0hh", want: "unexpected character 'h'", kind: dur.KindUnexpectedCharacter, offset: 2},
+		{name: "floating nanoseconds", input: "0,,1s", want: "unexpected token 'COMMA'", kind: dur.KindUnexpectedToken, offset: 1},
+		{name: "incomplete microseconds", input: "1u", want: "invalid character for microseconds 'EOF'", kind: dur.KindUnexpectedCharacter, offset: 2},
+		{name: "incomplete nanoseconds", input: "1nx", want: "invalid character for nanoseconds 'x'", kind: dur.KindUnexpectedCharacter, offset: 2},
+		{name: "missing end of term", input: ")", want: "unexpected closing parenthesis", kind: dur.KindUnexpectedToken, offset: 0},
+		{name: "unexpected end of term", input: ")1h", want: "unexpected closing parenthesis", kind: dur.KindUnexpectedToken, offset: 0},
+		{name: "missing begin of term", input: "1h)", want: "unexpected closing parenthesis", kind: dur.KindUnexpectedToken, offset: 2},
+		{name: "missing end of term", input: "(", want: "unexpected token 'EOF'", kind: dur.KindUnexpectedToken, offset: 1},
+		{name: "missing end of term", input: "(1h", want: "unexpected token 'EOF'", kind: dur.KindUnexpectedToken, offset: 3},
+		{name: "missing end of term", input: "1h(", want: "unexpected token 'EOF'", kind: dur.KindUnexpectedToken, offset: 3},
+		{name: "invalid operator", input: "--1h", want: "unexpected token 'MINUS'", kind: dur.KindUnexpectedToken, offset: 1},
+		{name: "invalid operator", input: "-+1h", want: "unexpected token 'PLUS'", kind: dur.KindUnexpectedToken, offset: 1},
+		{name: "parentheses", input: "1h(1h", want: "unexpected token 'EOF'", kind: dur.KindUnexpectedToken, offset: 5},
+		{name: "multiply 2 durations", input: "1h*1h", want: "cannot calculate 2 durations", kind: dur.KindInvalidOperation, offset: 2},
+		{name: "divide 2 durations", input: "1h/1h", want: "result is no duration", kind: dur.KindInvalidResult, offset: 0},
+		{name: "divide by zero duration", input: "1h/0s", want: "division by zero", kind: dur.KindInvalidOperation, offset: 2},
+		{name: "divide 2 durations", input: "2/1m", want: "cannot divide by a duration", kind: dur.KindInvalidOperation, offset: 1},
+		{name: "unknown function", input: "1h + foo(1h)", want: "unknown function 'foo'", kind: dur.KindUndefined, offset: 5},
+		{name: "undefined variable", input: "1h x", want: "undefined variable 'x'", kind: dur.KindUndefined, offset: 3},
+		{name: "undefined variable in later statement", input: "a = 1h; a + b", want: "undefined variable 'b'", kind: dur.KindUndefined, offset: 12},
+		{name: "previous statement in first statement", input: "_ + 1h", want: "undefined variable '_'", kind: dur.KindUndefined, offset: 0},
+		{name: "assignment without value", input: "a = ; a", want: "unexpected token 'SEMICOLON'", kind: dur.KindUnexpectedToken, offset: 4},
+		{name: "assignment to value", input: "1h = 2h", want: "unexpected token 'ASSIGN'", kind: dur.KindUnexpectedToken, offset: 3},
+		{name: "assignment in expression", input: "2 * (a = 1h)", want: "unexpected token 'ASSIGN'", kind: dur.KindUnexpectedToken, offset: 7},
+		{name: "statement in parentheses", input: "(1h; 2h)", want: "unexpected token 'SEMICOLON'", kind: dur.KindUnexpectedToken, offset: 3},
+		{name: "missing function argument", input: "ceil(1h)", want: "ceil expects 2 arguments, got 1", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "too many function arguments", input: "ceil(1h, 1m, 1s)", want: "ceil expects 2 arguments, got 3", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "function argument is no duration", input: "ceil(2, 1h)", want: "ceil requires a duration as argument 1, got number", kind: dur.KindInvalidOperation, offset: 5},
+		{name: "function unit is no duration", input: "round(1h, 10%)", want: "round requires a duration as argument 2, got percentage", kind: dur.KindInvalidOperation, offset: 10},
+		{name: "function unit is zero", input: "floor(1h, 0s)", want: "floor requires a positive unit, got 0s", kind: dur.KindInvalidValue, offset: 10},
+		{name: "function unit is negative", input: "floor(1h, -1m)", want: "floor requires a positive unit, got -1m0s", kind: dur.KindInvalidValue, offset: 10},
+		{name: "aggregate without arguments", input: "sum()", want: "sum expects at least 1 argument, got 0", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "aggregate of mixed arguments", input: "max(1h, 2)", want: "max requires all arguments to be durations, got number as argument 2", kind: dur.KindInvalidOperation, offset: 8},
+		{name: "aggregate of mixed arguments", input: "avg(1, 2, 3h)", want: "avg requires all arguments to be durations, got number as argument 1", kind: dur.KindInvalidOperation, offset: 4},
+		{name: "aggregate of percentages", input: "min(10%, 20%)", want: "min requires durations or numbers, got percentage", kind: dur.KindInvalidOperation, offset: 4},
+		{name: "abs with two arguments", input: "abs(1h, 2h)", want: "abs expects 1 argument, got 2", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "comma within call", input: "abs(1,2)", want: "abs expects 1 argument, got 2", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "clamp with inverted bounds", input: "clamp(1h, 8h, 2h)", want: "clamp requires lower bound 8h0m0s to be less than or equal to upper bound 2h0m0s", kind: dur.KindInvalidValue, offset: 10},
+		{name: "clamp of mixed arguments", input: "clamp(1h, 1, 2h)", want: "clamp requires all arguments to be durations, got number as argument 2", kind: dur.KindInvalidOperation, offset: 10},
+		{name: "lists of different length", input: "[1h, 2h] + [1h]", want: "cannot apply '+' to lists of length 2 and 1", kind: dur.KindInvalidOperation, offset: 9},
+		{name: "invalid element operation", input: "[1h, 2h] * [1h, 2h]", want: "cannot calculate 2 durations", kind: dur.KindInvalidOperation, offset: 9},
+		{name: "list is no duration", input: "[1h]", want: "result is no duration", kind: dur.KindInvalidResult, offset: 0},
+		{name: "unclosed list", input: "[1h, 2h", want: "unexpected token 'EOF'", kind: dur.KindUnexpectedToken, offset: 7},
+		{name: "trailing comma in list", input: "[1h, ]", want: "unexpected token 'BRACKET_CLOSE'", kind: dur.KindUnexpectedToken, offset: 5},
+		{name: "sum of empty list", input: "sum([])", want: "sum of an empty list is undefined", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "mixed list", input: "sum([1h, 2])", want: "sum requires all arguments to be durations, got number as argument 1", kind: dur.KindInvalidOperation, offset: 4},
+		{name: "list as clamp bound", input: "clamp(1h, [0s], 2h)", want: "clamp requires a duration or number as bound, got list", kind: dur.KindInvalidOperation, offset: 10},
+		{name: "list as rounding unit", input: "ceil(1h, [1m])", want: "ceil requires a duration as argument 2, got list", kind: dur.KindInvalidOperation, offset: 9},
+		{name: "overflow in list", input: "[1h, 2000000h] * 2", want: "overflow in '*': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 15},
+		{name: "add timestamps", input: "2026-10-18 + 2026-10-19", want: "cannot calculate timestamp + timestamp", kind: dur.KindInvalidOperation, offset: 11},
+		{name: "multiply timestamp", input: "2026-10-18 * 2", want: "cannot calculate timestamp * number", kind: dur.KindInvalidOperation, offset: 11},
+		{name: "subtract timestamp", input: "1h - 2026-10-18", want: "cannot calculate duration - timestamp", kind: dur.KindInvalidOperation, offset: 3},
+		{name: "percentage of timestamp", input: "2026-10-18 + 10%", want: "cannot calculate timestamp + percentage", kind: dur.KindInvalidOperation, offset: 11},
+		{name: "negate timestamp", input: "-2026-10-18", want: "cannot negate a timestamp", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "invalid timestamp", input: "1h + 2026-13-40", want: "invalid timestamp 2026-13-40", kind: dur.KindInvalidValue, offset: 5},
+		{name: "invalid clock time", input: "25:00-26:00", want: "invalid clock time 25:00", kind: dur.KindInvalidValue, offset: 0},
+		{name: "invalid minutes", input: "09:60", want: "invalid clock time 09:60", kind: dur.KindInvalidValue, offset: 0},
+		{name: "multiply clock time", input: "09:15*2", want: "cannot calculate clock * number", kind: dur.KindInvalidOperation, offset: 5},
+		{name: "add clock times", input: "09:15 + 10:00", want: "cannot calculate clock + clock", kind: dur.KindInvalidOperation, offset: 6},
+		{name: "negate clock time", input: "-09:15", want: "cannot negate a clock", kind: dur.KindInvalidOperation, offset: 0},
+		{name: "colon minutes out of range", input: "1h + 1:60:00.5", want: "invalid duration 1:60:00.5: value out of range", kind: dur.KindInvalidValue, offset: 5},
+		{name: "colon hours out of range", input: "1.24:00", want: "invalid duration 1.24:00: value out of range", kind: dur.KindInvalidValue, offset: 0},
+		{name: "colon duration with unit", input: "1:23:45.5h", want: "unexpected character 'h'", kind: dur.KindUnexpectedCharacter, offset: 9},
+		{name: "iso8601 years without anchor", input: "1h + P1Y", want: "designator Y requires an anchor date", kind: dur.KindInvalidValue, offset: 5},
+		{name: "iso8601 months without anchor", input: "P1M", want: "designator M requires an anchor date", kind: dur.KindInvalidValue, offset: 0},
+		{name: "unknown unit name", input: "1 hourx", want: "undefined variable 'hourx'", kind: dur.KindUndefined, offset: 2},
+		{name: "months without anchor", input: "2 months", want: "unit mo requires an anchor date", kind: dur.KindInvalidValue, offset: 0},
+		{name: "range to duration", input: "09:15..1h", want: "unexpected token 'DURATION'", kind: dur.KindUnexpectedToken, offset: 7},
+		{name: "timestamp is no duration", input: "2026-10-18 + 1h", want: "result is no duration", kind: dur.KindInvalidResult, offset: 0},
+		{name: "aggregate of timestamps", input: "min(2026-10-18)", want: "min requires durations or numbers, got timestamp", kind: dur.KindInvalidOperation, offset: 4},
+		{name: "empty function argument", input: "ceil(, 1h)", want: "unexpected token 'COMMA'", kind: dur.KindUnexpectedToken, offset: 5},
+		{name: "trailing function argument", input: "ceil(1h, )", want: "unexpected closing parenthesis", kind: dur.KindUnexpectedToken, offset: 9},
+		{name: "unclosed function call", input: "ceil(1h, 15m", want: "unexpected token 'EOF'", kind: dur.KindUnexpectedToken, offset: 12},
+		{name: "comma outside function", input: "1h, 2h", want: "unexpected token 'COMMA'", kind: dur.KindUnexpectedToken, offset: 2},
+		{name: "month without anchor", input: "1h+1mo", want: "unit mo requires an anchor date", kind: dur.KindInvalidValue, offset: 3},
+		{name: "year without anchor", input: "1y", want: "unit y requires an anchor date", kind: dur.KindInvalidValue, offset: 0},
+		{name: "divide by decimal zero", input: "1h/0.0", want: "division by zero", kind: dur.KindInvalidOperation, offset: 2},
+		{name: "result is decimal", input: "1.5", want: "result is no duration", kind: dur.KindInvalidResult, offset: 0},
+		{name: "modulo by zero", input: "1h mod 0s", want: "division by zero", kind: dur.KindInvalidOperation, offset: 3},
+		{name: "modulo of decimal", input: "1h mod 1.5", want: "modulo requires durations or integers", kind: dur.KindInvalidOperation, offset: 3},
+		{name: "modulo by duration", input: "2 mod 1h", want: "cannot divide by a duration", kind: dur.KindInvalidOperation, offset: 2},
+		{name: "percent before value", input: "20% + 1h", want: "cannot apply percentage with '+'", kind: dur.KindInvalidOperation, offset: 4},
+		{name: "percent modulo", input: "1h mod 20%", want: "cannot apply percentage with 'mod'", kind: dur.KindInvalidOperation, offset: 3},
+		{name: "divide by zero percent", input: "1h / 0%", want: "division by zero", kind: dur.KindInvalidOperation, offset: 3},
+		{name: "overflow in multiplication", input: "1562048h*2", want: "overflow in '*': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 8},
+		{name: "overflow in addition", input: "2562047h + 2562047h", want: "overflow in '+': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 9},
+		{name: "overflow in subtraction", input: "-2562047h - 2562047h", want: "overflow in '-': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 10},
+		{name: "overflow in division", input: "2562047h / 0.5", want: "overflow in '/': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 9},
+		{name: "overflow in percentage", input: "2562047h + 10%", want: "overflow in '+': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 9},
+		{name: "overflow in literal", input: "1h + 3000000h", want: "overflow in '3000000h': value exceeds the range of a duration", kind: dur.KindOverflow, offset: 5},
+		{name: "overflow in decimal literal", input: "2562047,9h", want: "overflow in '2562047,9h': value exceeds the range of a duration", kind: dur.KindOverflow, offset: 0},
+		{name: "overflow in compound literal", input: "2562047h47m17s", want: "overflow in '+': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 11},
+		{name: "overflow in negation", input: "-(2562047h47m16s854ms775us807ns+1ns)", want: "overflow in '+': result exceeds the range of a duration", kind: dur.KindOverflow, offset: 31},
+		{name: "divide by zero", input: "1h/0", want: "division by zero", kind: dur.KindInvalidOperation, offset: 2},
+		{name: "result is not duration", input: "2/1", want: "result is no duration", kind: dur.KindInvalidResult, offset: 0},
+		{name: "result is not duration", input: "2*1", want: "result is no duration", kind: dur.KindInvalidResult, offset: 0},
+		{name: "result is not duration", input: "1", want: "result is no duration", kind: dur.KindInvalidResult, offset: 0},
+		{name: "result is not duration", input: "1h+2+3", want: "result is no duration", kind: dur.KindInvalidOperation, offset: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dur.NewCalculator(tt.input).Calculate()
+
+			var calcErr *dur.Error
+			if !errors.As(err, &calcErr) {
+				t.Fatalf("Calculate() error = %v, want *dur.Error", err)
+			}
+
+			if calcErr.Error() != tt.want {
+				t.Errorf("Calculate() error = %v, want %v", calcErr, tt.want)
+			}
+
+			if calcErr.Kind != tt.kind {
+				t.Errorf("Calculate() error kind = %v, want %v", calcErr.Kind, tt.kind)
+			}
+
+			if calcErr.Offset != tt.offset {
+				t.Errorf("Calculate() error offset = %v, want %v", calcErr.Offset, tt.offset)
+			}
+		})
+	}
+}
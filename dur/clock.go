@@ -0,0 +1,100 @@
+package dur
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockPattern matches times of day like 09:15 or 9:15:30.
+var clockPattern = regexp.MustCompile(`^\d{1,2}:\d{2}(?::\d{2})?`)
+
+const day = 24 * time.Hour
+
+// clock is a time of day as exact amount of nanoseconds since midnight.
+type clock struct {
+	ns *big.Rat
+}
+
+// parseClock parses a time of day like 09:15 or 17:45:30. 24:00 is the end of the day.
+func parseClock(lit string) (clock, error) {
+	var parts = strings.Split(lit, ":")
+
+	h, _ := strconv.Atoi(parts[0])
+	m, _ := strconv.Atoi(parts[1])
+
+	var s int
+	if len(parts) == 3 {
+		s, _ = strconv.Atoi(parts[2])
+	}
+
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	if m > 59 || s > 59 || d > day {
+		return clock{}, fmt.Errorf("invalid clock time %v", lit)
+	}
+
+	return clock{ns: toRat(d)}, nil
+}
+
+// wrapDay returns ns modulo 24h in the range [0, 24h).
+func wrapDay(ns *big.Rat) *big.Rat {
+	var (
+		length = toRat(day)
+		days   = new(big.Int).Div(new(big.Int).Mul(ns.Num(), length.Denom()), new(big.Int).Mul(ns.Denom(), length.Num()))
+	)
+
+	return new(big.Rat).Sub(ns, new(big.Rat).Mul(new(big.Rat).SetInt(days), length))
+}
+
+// between returns the duration from c to end, wrapping past midnight, so 22:00..06:00 is 8h.
+func (c clock) between(end clock) duration {
+	d := new(big.Rat).Sub(end.ns, c.ns)
+	if d.Sign() < 0 {
+		d.Add(d, toRat(day))
+	}
+
+	return duration{ns: d}
+}
+
+func (c clock) String() string {
+	var (
+		ns      = truncate(c.ns).Int64()
+		h, m, s = ns / int64(time.Hour), ns / int64(time.Minute) % 60, ns % int64(time.Minute)
+	)
+
+	if s == 0 {
+		return fmt.Sprintf("%02d:%02d", h, m)
+	}
+
+	var sec = formatFraction(big.NewInt(s), big.NewInt(int64(time.Second)))
+	if s < int64(10*time.Second) {
+		sec = "0" + sec
+	}
+
+	return fmt.Sprintf("%02d:%02d:%v", h, m, sec)
+}
+
+// applyClock shifts times of day by durations, wrapping past midnight. Like a range, the difference of two
+// times of day is the duration from the first to the second one, so a - b with a = 22:00 and b = 06:00 is 8h.
+func applyClock(op Token, v1, v2 interface{}) (interface{}, error) {
+	c1, isClock1 := v1.(clock)
+	c2, isClock2 := v2.(clock)
+	d1, isDur1 := v1.(duration)
+	d2, isDur2 := v2.(duration)
+
+	switch {
+	case op.Type == TypePlus && isClock1 && isDur2:
+		return clock{ns: wrapDay(new(big.Rat).Add(c1.ns, d2.ns))}, nil
+	case op.Type == TypePlus && isDur1 && isClock2:
+		return clock{ns: wrapDay(new(big.Rat).Add(d1.ns, c2.ns))}, nil
+	case op.Type == TypeMinus && isClock1 && isDur2:
+		return clock{ns: wrapDay(new(big.Rat).Sub(c1.ns, d2.ns))}, nil
+	case op.Type == TypeMinus && isClock1 && isClock2:
+		return c1.between(c2), nil
+	default:
+		return nil, newTokenError(KindInvalidOperation, op, "cannot calculate %v %v %v", typeName(v1), opSymbol(op.Type), typeName(v2))
+	}
+}
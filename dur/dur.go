@@ -0,0 +1,96 @@
+// Package dur implements a small expression language for calculating with durations.
+//
+// An expression combines duration values like 1h30m or 0,5h and integers with the operators
+// +, -, * and / as well as parentheses. Values that follow each other without an operator are added.
+// Functions like ceil(7h52m, 15m) are built in or defined with def overtime(x) = max(0, x - 8h),
+// variables are assigned in statements separated by semicolons or newlines like rate = 45m; rate*7.
+// Lists like [1h, 2h] are calculated element-wise. Timestamps like 2026-10-18T09:12 can be shifted
+// by durations, the difference of two timestamps is a duration. Ranges of times of day like 09:15-17:45
+// are the duration between them, wrapping past midnight. Durations may also be written in colon notation
+// like 2.04:30:00, see ColonDurations, or in ISO 8601 like PT1H30M. Units may also be spelled out
+// like 90 min or 5 Minuten, see Locale.
+package dur
+
+import "time"
+
+// Expr is a parsed expression that can be evaluated repeatedly.
+type Expr struct {
+	input   string
+	tokens  []Token
+	root    Node
+	options []Option
+}
+
+// Parse scans and parses input into an Expr. The options are applied whenever the Expr is evaluated,
+// LegacyPrecedence also affects the shape of the syntax tree.
+func Parse(input string, opts ...Option) (*Expr, error) {
+	var (
+		o = newOptions(opts)
+		s = NewScanner(input)
+	)
+
+	s.colonDurations = o.colonDurations
+
+	tokens, err := s.Tokens()
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parse(tokens, o.legacyPrecedence)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expr{input: input, tokens: tokens, root: root, options: opts}, nil
+}
+
+// Root returns the root node of the syntax tree. It is nil for an empty expression.
+func (e *Expr) Root() Node {
+	return e.root
+}
+
+// Tokens returns the tokens of the expression.
+func (e *Expr) Tokens() []Token {
+	return append([]Token(nil), e.tokens...)
+}
+
+// String returns the input the expression was parsed from.
+func (e *Expr) String() string {
+	return e.input
+}
+
+// Eval evaluates the expression, which must result in a duration.
+func (e *Expr) Eval() (time.Duration, error) {
+	c := NewCalculator(e.input, e.options...)
+	c.root, c.parsed = e.root, true
+
+	return c.Calculate()
+}
+
+// Evaluate evaluates the expression, which may result in a duration or a number.
+func (e *Expr) Evaluate() (Result, error) {
+	c := NewCalculator(e.input, e.options...)
+	c.root, c.parsed = e.root, true
+
+	return c.Evaluate()
+}
+
+// Evaluate parses and evaluates input, which may result in a duration or a number.
+func Evaluate(input string, opts ...Option) (Result, error) {
+	return NewCalculator(input, opts...).Evaluate()
+}
+
+// Eval parses and evaluates input, which must result in a duration.
+func Eval(input string, opts ...Option) (time.Duration, error) {
+	return NewCalculator(input, opts...).Calculate()
+}
+
+// MustEval is like Eval but panics if input cannot be evaluated.
+func MustEval(input string, opts ...Option) time.Duration {
+	d, err := Eval(input, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
@@ -0,0 +1,319 @@
+package dur
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	picosecondsPerNano = 1000
+
+	calendarDay  = 24 * time.Hour
+	calendarWeek = 7 * calendarDay
+	workDay      = 8 * time.Hour
+	workWeek     = 5 * workDay
+)
+
+// units defines the length of the units d and w, which depends on whether calendar or work days are used.
+type units struct {
+	day  time.Duration
+	week time.Duration
+}
+
+// duration is an exact amount of nanoseconds. Fractions of nanoseconds are kept during evaluation,
+// the result is rounded to a time.Duration only once, see RoundingMode. With BigPrecision the result is
+// rounded to picoseconds instead and not limited in range.
+type duration struct {
+	ns *big.Rat
+}
+
+func durationOf(d time.Duration) duration {
+	return duration{ns: new(big.Rat).SetInt64(int64(d))}
+}
+
+var (
+	minDuration = new(big.Rat).SetInt64(math.MinInt64)
+	maxDuration = new(big.Rat).SetInt64(math.MaxInt64)
+)
+
+// clamp limits d to the range of time.Duration.
+func clamp(d duration) duration {
+	switch {
+	case d.ns.Cmp(minDuration) < 0:
+		return duration{ns: minDuration}
+	case d.ns.Cmp(maxDuration) > 0:
+		return duration{ns: maxDuration}
+	default:
+		return d
+	}
+}
+
+// durationBetween returns the exact duration from t to u. Unlike time.Time.Sub it does not saturate.
+func durationBetween(t, u time.Time) duration {
+	ns := new(big.Int).Mul(big.NewInt(u.Unix()-t.Unix()), big.NewInt(int64(time.Second)))
+	ns.Add(ns, big.NewInt(int64(u.Nanosecond()-t.Nanosecond())))
+
+	return duration{ns: new(big.Rat).SetInt(ns)}
+}
+
+// colonPattern matches durations in colon notation [d.]h:mm[:ss[.fff]] like 1:23:45 or 2.04:30:00.
+var colonPattern = regexp.MustCompile(`^(?:\d+\.)?\d+:\d{2}(?::\d{2}(?:\.\d+)?)?`)
+
+// parseDuration parses a single value with unit like 1h, 0,5h or 1.25ms exactly,
+// or a value in colon notation like 1:23:45.
+func parseDuration(lit string, u units) (duration, error) {
+	if strings.Contains(lit, ":") {
+		return parseColon(lit)
+	}
+
+	var number, unit = splitUnit(lit)
+
+	length, ok := u.length(unit)
+	if !ok {
+		return duration{}, fmt.Errorf("invalid duration %v: unknown unit %v", lit, unit)
+	}
+
+	value, err := parseDecimal(number)
+	if err != nil {
+		return duration{}, fmt.Errorf("invalid duration %v: %v", lit, err)
+	}
+
+	return duration{ns: value.Mul(value, length)}, nil
+}
+
+// splitUnit splits a value like 1,5h or 90 min into its number and unit. Spoken unit names like min are
+// returned as their unit like m.
+func splitUnit(lit string) (string, string) {
+	var (
+		i    = strings.LastIndexAny(lit, "0123456789") + 1
+		unit = strings.TrimLeft(lit[i:], " \t")
+	)
+
+	if u, ok := unitOf(unit); ok {
+		unit = u
+	}
+
+	return lit[:i], unit
+}
+
+// parseColon parses a duration in colon notation [d.]h:mm[:ss[.fff]]. Days are always 24h long,
+// like in the output of stopwatches or .NET TimeSpan.
+func parseColon(lit string) (duration, error) {
+	var days, rest = "0", lit
+	if i := strings.Index(lit, "."); i >= 0 && i < strings.Index(lit, ":") {
+		days, rest = lit[:i], lit[i+1:]
+	}
+
+	var (
+		parts = strings.Split(rest, ":")
+		sec   = "0"
+	)
+
+	if len(parts) == 3 {
+		sec = parts[2]
+	}
+
+	d, errD := strconv.ParseInt(days, 10, 64)
+	h, errH := strconv.ParseInt(parts[0], 10, 64)
+	m, _ := strconv.ParseInt(parts[1], 10, 64)
+	s, errS := parseDecimal(sec)
+
+	switch {
+	case errD != nil || errH != nil || errS != nil:
+		return duration{}, fmt.Errorf("invalid duration %v", lit)
+	case m > 59 || s.Cmp(big.NewRat(60, 1)) >= 0 || days != "0" && h > 23:
+		return duration{}, fmt.Errorf("invalid duration %v: value out of range", lit)
+	}
+
+	minutes := new(big.Int).Mul(big.NewInt(d), big.NewInt(24))
+	minutes.Add(minutes, big.NewInt(h)).Mul(minutes, big.NewInt(60)).Add(minutes, big.NewInt(m))
+
+	ns := new(big.Rat).Mul(new(big.Rat).SetInt(minutes), toRat(time.Minute))
+
+	return duration{ns: ns.Add(ns, s.Mul(s, toRat(time.Second)))}, nil
+}
+
+// length returns the length of unit in nanoseconds.
+func (u units) length(unit string) (*big.Rat, bool) {
+	switch unit {
+	case "ps":
+		return big.NewRat(1, picosecondsPerNano), true
+	case "ns":
+		return toRat(time.Nanosecond), true
+	case "us":
+		return toRat(time.Microsecond), true
+	case "ms":
+		return toRat(time.Millisecond), true
+	case "s":
+		return toRat(time.Second), true
+	case "m":
+		return toRat(time.Minute), true
+	case "h":
+		return toRat(time.Hour), true
+	case "d":
+		return toRat(u.day), true
+	case "w":
+		return toRat(u.week), true
+	default:
+		return nil, false
+	}
+}
+
+// picoseconds rounds d to whole picoseconds, the base unit of BigPrecision.
+func (m RoundingMode) picoseconds(d duration) duration {
+	ps := m.round(new(big.Rat).Mul(d.ns, big.NewRat(picosecondsPerNano, 1)))
+
+	return duration{ns: new(big.Rat).SetFrac(ps, big.NewInt(picosecondsPerNano))}
+}
+
+// formatDuration formats d like time.Duration.String, but without limit of range and with a precision
+// of picoseconds. Smaller fractions are truncated.
+func formatDuration(d duration) string {
+	var (
+		ps   = truncate(new(big.Rat).Mul(d.ns, big.NewRat(picosecondsPerNano, 1)))
+		sign = ""
+	)
+
+	if ps.Sign() < 0 {
+		sign = "-"
+		ps.Neg(ps)
+	}
+
+	var (
+		second = big.NewInt(int64(time.Second) * picosecondsPerNano)
+		sb     = strings.Builder{}
+	)
+
+	sb.WriteString(sign)
+
+	switch {
+	case ps.Sign() == 0:
+		return "0s"
+	case ps.Cmp(big.NewInt(picosecondsPerNano)) < 0:
+		sb.WriteString(ps.String() + "ps")
+	case ps.Cmp(second) < 0:
+		var unit = "ns"
+
+		scale := big.NewInt(picosecondsPerNano)
+		for _, u := range []string{"µs", "ms"} {
+			next := new(big.Int).Mul(scale, big.NewInt(1000))
+			if ps.Cmp(next) < 0 {
+				break
+			}
+
+			unit, scale = u, next
+		}
+
+		sb.WriteString(formatFraction(ps, scale) + unit)
+	default:
+		minutes, fraction := new(big.Int).QuoRem(ps, new(big.Int).Mul(second, big.NewInt(60)), new(big.Int))
+		hours, minutes := new(big.Int).QuoRem(minutes, big.NewInt(60), new(big.Int))
+
+		if hours.Sign() > 0 {
+			sb.WriteString(hours.String() + "h")
+		}
+
+		if hours.Sign() > 0 || minutes.Sign() > 0 {
+			sb.WriteString(minutes.String() + "m")
+		}
+
+		sb.WriteString(formatFraction(fraction, second) + "s")
+	}
+
+	return sb.String()
+}
+
+// formatColon formats d in colon notation [d.]h:mm:ss[.fff] with a precision of picoseconds,
+// days are written from 24h on.
+func formatColon(d duration) string {
+	var (
+		ps   = truncate(new(big.Rat).Mul(d.ns, big.NewRat(picosecondsPerNano, 1)))
+		sign = ""
+	)
+
+	if ps.Sign() < 0 {
+		sign = "-"
+		ps.Neg(ps)
+	}
+
+	var (
+		second            = big.NewInt(int64(time.Second) * picosecondsPerNano)
+		seconds, fraction = new(big.Int).QuoRem(ps, second, new(big.Int))
+		days, rest        = new(big.Int).QuoRem(seconds, big.NewInt(int64(calendarDay/time.Second)), new(big.Int))
+		h, m, s           = rest.Int64() / 3600, rest.Int64() / 60 % 60, rest.Int64() % 60
+		sec               = formatFraction(fraction.Add(fraction, new(big.Int).Mul(big.NewInt(s), second)), second)
+	)
+
+	if s < 10 {
+		sec = "0" + sec
+	}
+
+	if days.Sign() > 0 {
+		return fmt.Sprintf("%v%v.%02d:%02d:%v", sign, days, h, m, sec)
+	}
+
+	return fmt.Sprintf("%v%v:%02d:%v", sign, h, m, sec)
+}
+
+// formatFraction formats v/scale with as many fractional digits as needed, scale must be a power of ten.
+func formatFraction(v, scale *big.Int) string {
+	q, r := new(big.Int).QuoRem(v, scale, new(big.Int))
+	if r.Sign() == 0 {
+		return q.String()
+	}
+
+	var (
+		digits   = r.String()
+		fraction = strings.Repeat("0", len(scale.String())-1-len(digits)) + digits
+	)
+
+	return q.String() + "." + strings.TrimRight(fraction, "0")
+}
+
+// isCalendarUnit reports whether lit is a value of the units mo or y, which have no fixed length.
+func isCalendarUnit(lit string) bool {
+	_, unit := splitUnit(lit)
+
+	return unit == "mo" || unit == "y"
+}
+
+// calendarMonths returns the number of months of a value of the units mo or y like 2mo or 1y.
+// They have no fixed length, so an anchor date from is required to resolve them.
+func calendarMonths(lit string, from time.Time) (int, error) {
+	var number, unit = splitUnit(lit)
+
+	if from.IsZero() {
+		return 0, fmt.Errorf("unit %v requires an anchor date", unit)
+	}
+
+	if strings.ContainsAny(number, ",.") {
+		return 0, fmt.Errorf("floating point values for unit %v is not supported", unit)
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %v: %v", lit, err)
+	}
+
+	if unit == "y" {
+		n *= 12
+	}
+
+	return n, nil
+}
+
+// addMonths adds n months to t like time.AddDate, but if the day does not exist in the target month
+// it yields the last day of that month instead of overflowing into the next one, so 2026-01-31 + 1mo = 2026-02-28.
+func addMonths(t time.Time, n int) time.Time {
+	r := t.AddDate(0, n, 0)
+	if r.Day() != t.Day() {
+		r = r.AddDate(0, 0, -r.Day())
+	}
+
+	return r
+}
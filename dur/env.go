@@ -0,0 +1,89 @@
+package dur
+
+import (
+	"io"
+	"math/big"
+	"time"
+)
+
+// Env is a scope of variables. Lookups that fail in a scope continue in its parent, assignments are made
+// in the scope itself. An Env can be pre-populated and passed to a Calculator with Environment.
+type Env struct {
+	parent *Env
+	vars   map[string]interface{}
+	funcs  map[string]function
+}
+
+// NewEnv returns an empty scope. parent may be nil.
+func NewEnv(parent *Env) *Env {
+	return &Env{parent: parent, vars: map[string]interface{}{}, funcs: map[string]function{}}
+}
+
+// Load evaluates the statements read from r in this scope, so the variables and functions they define
+// are available to expressions evaluated with Environment.
+func (e *Env) Load(r io.Reader, opts ...Option) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	c := NewCalculator(string(src), opts...)
+	if err := c.parse(); err != nil {
+		return err
+	}
+
+	c.scope = e
+	_, err = c.evaluate(c.root)
+
+	return err
+}
+
+// SetDuration sets the variable name to a duration.
+func (e *Env) SetDuration(name string, d time.Duration) {
+	e.set(name, durationOf(d))
+}
+
+// SetNumber sets the variable name to a number.
+func (e *Env) SetNumber(name string, n *big.Rat) {
+	e.set(name, normalize(new(big.Rat).Set(n)))
+}
+
+// SetResult sets the variable name to the result of an earlier evaluation.
+func (e *Env) SetResult(name string, r Result) {
+	e.set(name, exact(r.v))
+}
+
+// Lookup returns the value of the variable name from this scope or one of its parents. Like the result of
+// an evaluation, durations are rounded toward zero to time.Duration and timestamps are returned as time.Time.
+func (e *Env) Lookup(name string) (Result, bool) {
+	v, ok := e.lookup(name)
+	if !ok {
+		return Result{}, false
+	}
+
+	return Result{v: RoundTowardZero.result(v, false)}, true
+}
+
+func (e *Env) set(name string, v interface{}) {
+	e.vars[name] = v
+}
+
+func (e *Env) lookupFunc(name string) (function, bool) {
+	for s := e; s != nil; s = s.parent {
+		if f, ok := s.funcs[name]; ok {
+			return f, true
+		}
+	}
+
+	return function{}, false
+}
+
+func (e *Env) lookup(name string) (interface{}, bool) {
+	for s := e; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
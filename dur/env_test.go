@@ -0,0 +1,135 @@
+package dur_test
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oppodelldog/dur/dur"
+)
+
+func TestEnv_Lookup(t *testing.T) {
+	global := dur.NewEnv(nil)
+	global.SetDuration("rate", 45*time.Minute)
+	global.SetNumber("sessions", big.NewRat(7, 1))
+
+	local := dur.NewEnv(global)
+	local.SetDuration("rate", time.Hour)
+
+	type testCase struct {
+		name string
+		env  *dur.Env
+		key  string
+		want string
+		ok   bool
+	}
+
+	tests := []testCase{
+		{name: "global", env: global, key: "rate", want: "45m0s", ok: true},
+		{name: "shadowed", env: local, key: "rate", want: "1h0m0s", ok: true},
+		{name: "parent", env: local, key: "sessions", want: "7", ok: true},
+		{name: "undefined", env: local, key: "buffer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.env.Lookup(tt.key)
+			if ok != tt.ok {
+				t.Fatalf("Lookup() ok = %v, want %v", ok, tt.ok)
+			}
+
+			if ok && got.String() != tt.want {
+				t.Errorf("Lookup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnv_Load(t *testing.T) {
+	const defs = `
+def overtime(x: duration) = max(0, x - workday)
+def buffered(x) = x*1.2 + 30m
+
+workday = 8h
+`
+
+	env := dur.NewEnv(nil)
+	if err := env.Load(strings.NewReader(defs)); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got, err := dur.Eval("overtime(buffered(7h))", dur.Environment(env))
+	if want := 54 * time.Minute; err != nil || got != want {
+		t.Errorf("Eval() = %v, %v, want %v", got, err, want)
+	}
+
+	err = dur.NewEnv(nil).Load(strings.NewReader("def f(x) = x\nf(1h, 2h)"))
+
+	var calcErr *dur.Error
+	if !errors.As(err, &calcErr) || calcErr.Offset != 13 {
+		t.Errorf("Load() error = %v, want error at offset 13", err)
+	}
+}
+
+func TestEnv_Calculator(t *testing.T) {
+	env := dur.NewEnv(nil)
+	env.SetDuration("rate", 45*time.Minute)
+
+	c := dur.NewCalculator("rate = rate * 2; rate", dur.Environment(env))
+
+	for run := 0; run < 2; run++ {
+		got, err := c.Calculate()
+		if err != nil {
+			t.Fatalf("Calculate() error = %v", err)
+		}
+
+		if want := 90 * time.Minute; got != want {
+			t.Errorf("Calculate() run %v = %v, want %v", run, got, want)
+		}
+	}
+
+	if got, _ := env.Lookup("rate"); got.String() != "45m0s" {
+		t.Errorf("Lookup() = %v, want environment to be unchanged", got)
+	}
+
+	r, err := dur.Evaluate("1h/15m")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	env.SetResult("slots", r)
+
+	if got, err := dur.Eval("slots * rate", dur.Environment(env)); err != nil || got != 3*time.Hour {
+		t.Errorf("Eval() = %v, %v, want %v", got, err, 3*time.Hour)
+	}
+}
+
+func TestEnv_LookupResult(t *testing.T) {
+	const defs = `
+start = 2026-10-18T09:12
+break = 1h/3
+big = 2000000h*2
+`
+
+	env := dur.NewEnv(nil)
+	if err := env.Load(strings.NewReader(defs), dur.BigPrecision); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	start, _ := env.Lookup("start")
+	if got, ok := start.Time(); !ok || !start.IsTime() || !got.Equal(time.Date(2026, time.October, 18, 9, 12, 0, 0, time.UTC)) {
+		t.Errorf("Lookup(start).Time() = %v, %v, want 2026-10-18T09:12:00Z", got, ok)
+	}
+
+	brk, _ := env.Lookup("break")
+	if got, ok := brk.Duration(); !ok || got != 20*time.Minute {
+		t.Errorf("Lookup(break).Duration() = %v, %v, want 20m0s", got, ok)
+	}
+
+	big, _ := env.Lookup("big")
+	if got := big.String(); got != "4000000h0m0s" {
+		t.Errorf("Lookup(big) = %v, want 4000000h0m0s", got)
+	}
+}
@@ -0,0 +1,38 @@
+package dur
+
+import "fmt"
+
+// ErrorKind classifies why an input could not be scanned or calculated.
+type ErrorKind string
+
+const (
+	KindUnexpectedCharacter ErrorKind = "UNEXPECTED_CHARACTER"
+	KindUnexpectedToken     ErrorKind = "UNEXPECTED_TOKEN"
+	KindInvalidValue        ErrorKind = "INVALID_VALUE"
+	KindInvalidOperation    ErrorKind = "INVALID_OPERATION"
+	KindInvalidResult       ErrorKind = "INVALID_RESULT"
+	KindOverflow            ErrorKind = "OVERFLOW"
+	KindUndefined           ErrorKind = "UNDEFINED"
+	KindRecursionLimit      ErrorKind = "RECURSION_LIMIT"
+)
+
+// Error is returned by Scanner and Calculator. Offset is the byte offset in the input where the error occurred,
+// Token is the offending token if there is one.
+type Error struct {
+	Kind    ErrorKind
+	Offset  int
+	Token   Token
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind ErrorKind, offset int, format string, a ...interface{}) *Error {
+	return &Error{Kind: kind, Offset: offset, Message: fmt.Sprintf(format, a...)}
+}
+
+func newTokenError(kind ErrorKind, tok Token, format string, a ...interface{}) *Error {
+	return &Error{Kind: kind, Offset: tok.Pos, Token: tok, Message: fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,248 @@
+package dur_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Oppodelldog/dur/dur"
+)
+
+func ExampleEval() {
+	d, err := dur.Eval("8h*5 - 2h30m")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(d)
+	// Output: 37h30m0s
+}
+
+func ExampleEvaluate() {
+	slots, err := dur.Evaluate("8h/45m")
+	if err != nil {
+		panic(err)
+	}
+
+	exact, _ := slots.Number()
+
+	fmt.Println(slots, exact)
+	// Output: 10.666666667 32/3
+}
+
+func ExampleMustEval() {
+	fmt.Println(dur.MustEval("0,5h + 1h/4"))
+	// Output: 45m0s
+}
+
+func ExampleParse() {
+	expr, err := dur.Parse("1h30m / 2")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, tok := range expr.Tokens() {
+		fmt.Printf("%d %s %q\n", tok.Pos, tok.Type, tok.Literal)
+	}
+
+	fmt.Println(expr.Eval())
+	// Output:
+	// 0 DURATION "1h"
+	// 2 DURATION "30m"
+	// 6 DIVIDE ""
+	// 8 INTEGER "2"
+	// 9 EOF ""
+	// 45m0s <nil>
+}
+
+func ExampleLegacyPrecedence() {
+	fmt.Println(dur.MustEval("10m+20m*2"))
+	fmt.Println(dur.MustEval("10m+20m*2", dur.LegacyPrecedence))
+	// Output:
+	// 50m0s
+	// 1h0m0s
+}
+
+func ExampleHumanReadablePrinter() {
+	fmt.Println(dur.MustEval("12h - 1m + 60s", dur.HumanReadablePrinter))
+	// Output:
+	//      12h0m0s -         1m0s =     11h59m0s
+	//     11h59m0s +         1m0s =      12h0m0s
+	// 12h0m0s
+}
+
+func ExampleHumanReadablePrinter_functions() {
+	fmt.Println(dur.MustEval("ceil(7h52m, 15m) + 30m", dur.HumanReadablePrinter))
+	// Output:
+	//      7h0m0s +        52m0s =      7h52m0s
+	//        ceil(7h52m0s, 15m0s) =       8h0m0s
+	//       8h0m0s +        30m0s =      8h30m0s
+	// 8h30m0s
+}
+
+func ExampleNanoPrinter_functions() {
+	fmt.Println(dur.MustEval("avg(1s, 2s, 4s)", dur.NanoPrinter))
+	// Output:
+	//          avg(1000000000, 2000000000, 4000000000) = 2333333333.333333333
+	// 2.333333333s
+}
+
+func ExampleEnvironment() {
+	env := dur.NewEnv(nil)
+	env.SetDuration("rate", 45*time.Minute)
+	env.SetNumber("sessions", big.NewRat(7, 1))
+
+	fmt.Println(dur.MustEval("buffer = 10m; rate*sessions + buffer", dur.Environment(env)))
+
+	_, ok := env.Lookup("buffer")
+	fmt.Println(ok)
+	// Output:
+	// 5h25m0s
+	// false
+}
+
+func ExampleHumanReadablePrinter_lists() {
+	fmt.Println(dur.MustEval("sum([1h, 2h30m] * 2)", dur.HumanReadablePrinter))
+	// Output:
+	//       2h0m0s +        30m0s =      2h30m0s
+	// [1h0m0s, 2h30m0s] *            2 = [2h0m0s, 5h0m0s]
+	//       sum([2h0m0s, 5h0m0s]) =       7h0m0s
+	// 7h0m0s
+}
+
+func ExampleEvaluate_timestamps() {
+	finished, _ := dur.Evaluate("2026-10-18T09:12 + 3h47m", dur.HumanReadablePrinter)
+	fmt.Println(finished)
+
+	took, _ := dur.Evaluate("2026-10-18 14:03:10 - 2026-10-18 09:12:00")
+	fmt.Println(took)
+	// Output:
+	//       3h0m0s +        47m0s =      3h47m0s
+	// 2026-10-18T09:12:00Z +      3h47m0s = 2026-10-18T12:59:00Z
+	// 2026-10-18T12:59:00Z
+	// 4h51m10s
+}
+
+func ExampleEvaluate_clock() {
+	worked, _ := dur.Evaluate("08:30-12:00 12:45-17:15", dur.HumanReadablePrinter)
+	fmt.Println(worked)
+
+	night, _ := dur.Evaluate("22:00..06:00")
+	fmt.Println(night)
+	// Output:
+	//                08:30..12:00 =      3h30m0s
+	//                12:45..17:15 =      4h30m0s
+	//      3h30m0s +      4h30m0s =       8h0m0s
+	// 8h0m0s
+	// 8h0m0s
+}
+
+func ExampleOutput() {
+	r, _ := dur.Evaluate("1:23:45 + 0:40", dur.ColonDurations, dur.Output(dur.FormatColon))
+	fmt.Println(r)
+	// Output:
+	// 2:03:45
+}
+
+func ExampleFormatISO8601() {
+	r, _ := dur.Evaluate("PT8H - PT30M + 0.5s", dur.Output(dur.FormatISO8601))
+	fmt.Println(r)
+	// Output:
+	// PT7H30M0.5S
+}
+
+func ExampleFormatDecimal() {
+	r, _ := dur.Evaluate("8h20m", dur.Output(dur.FormatDecimal), dur.OutputUnit("h"), dur.Decimals(2))
+	fmt.Println(r)
+	// Output:
+	// 8.33h
+}
+
+func ExampleFormatCompact() {
+	r, _ := dur.Evaluate("36h", dur.Output(dur.FormatCompact), dur.MaxUnit("d"))
+	fmt.Println(r)
+	// Output:
+	// 1d12h
+}
+
+func ExampleFormatHuman() {
+	r, _ := dur.Evaluate("2h29m40s", dur.Output(dur.FormatHuman), dur.Language("de"), dur.Granularity(time.Minute))
+	fmt.Println(r)
+
+	r, _ = dur.Evaluate("2h40m", dur.Output(dur.FormatHuman), dur.Approximate)
+	fmt.Println(r)
+	// Output:
+	// 2 Stunden 30 Minuten
+	// about 3 hours
+}
+
+func ExampleError() {
+	_, err := dur.Eval("1h * 2h")
+
+	var durErr *dur.Error
+	if errors.As(err, &durErr) {
+		fmt.Println(durErr.Kind, durErr.Offset, durErr.Token.Type, durErr)
+	}
+	// Output: INVALID_OPERATION 3 MULTIPLY cannot calculate 2 durations
+}
+
+func ExampleInspect() {
+	expr, err := dur.Parse("8h*5 - 2h30m")
+	if err != nil {
+		panic(err)
+	}
+
+	dur.Inspect(expr.Root(), func(n dur.Node) bool {
+		if lit, ok := n.(*dur.Literal); ok {
+			fmt.Println(lit.Token.Literal, lit.Span())
+		}
+
+		return true
+	})
+	// Output:
+	// 8h {0 2}
+	// 5 {3 4}
+	// 2h {7 9}
+	// 30m {9 12}
+}
+
+func ExampleFrom() {
+	from := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	fmt.Println(dur.MustEval("1mo", dur.From(from), dur.HumanReadablePrinter))
+	// Output:
+	//          1mo = 2026-01-31 .. 2026-02-28 =     672h0m0s
+	// 672h0m0s
+}
+
+func ExampleFrom_iso8601() {
+	from := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	fmt.Println(dur.MustEval("P1M + PT1H", dur.From(from), dur.HumanReadablePrinter))
+	// Output:
+	//           P1M = 2026-01-31 .. 2026-02-28 =     672h0m0s
+	//     672h0m0s +       1h0m0s =     673h0m0s
+	// 673h0m0s
+}
+
+func ExampleBigPrecision() {
+	r, err := dur.Evaluate("300 * 365d + 1ps", dur.BigPrecision, dur.HumanReadablePrinter)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(r)
+	// Output:
+	//          300 *    8760h0m0s = 2628000h0m0s
+	// 2628000h0m0s +          1ps = 2628000h0m0.000000000001s
+	// 2628000h0m0.000000000001s
+}
+
+func ExampleNanoPrinter() {
+	fmt.Println(dur.MustEval("40h * 80% + 20%", dur.NanoPrinter))
+	// Output:
+	//    144000000000000 *                80% =    115200000000000
+	//    115200000000000 +                20% =    138240000000000
+	// 38h24m0s
+}
@@ -0,0 +1,208 @@
+package dur
+
+import (
+	"math/big"
+	"strings"
+	"time"
+)
+
+// OutputFormat defines how Result.String formats durations, see Output.
+type OutputFormat int
+
+const (
+	// FormatDefault formats durations like time.Duration.String. This is the default.
+	FormatDefault OutputFormat = iota
+	// FormatColon formats durations in colon notation [d.]h:mm:ss[.fff] like 1:23:45 or 2.04:30:00.
+	FormatColon
+	// FormatISO8601 formats durations in ISO 8601 of hours, minutes and seconds like PT1H30M or PT0.5S.
+	FormatISO8601
+	// FormatDecimal formats durations as decimal number of a single unit like 8.25h, see OutputUnit and Decimals.
+	// Without OutputUnit the largest unit up to MaxUnit that is not larger than the duration is used.
+	FormatDecimal
+	// FormatCompact formats durations like time.Duration.String without zero values, like 12h or 1h30s.
+	// Units up to MaxUnit are used.
+	FormatCompact
+	// FormatInt formats durations as integral number of OutputUnit without suffix like 43200, seconds by default.
+	// Fractions are truncated.
+	FormatInt
+	// FormatHuman formats durations in words like 2 hours 30 minutes with units up to MaxUnit,
+	// see Language, Granularity and Approximate.
+	FormatHuman
+)
+
+// outputUnits are the units a duration can be formatted in, in ascending order.
+var outputUnits = []string{"ps", "ns", "us", "ms", "s", "m", "h", "d", "w"}
+
+// formatter formats the durations of a result in an OutputFormat.
+type formatter struct {
+	format      OutputFormat
+	unit        string
+	decimals    int
+	maxUnit     string
+	units       units
+	lang        string
+	granularity *big.Int
+	approximate bool
+}
+
+// validate reports an unknown unit, largest unit or language.
+func (f formatter) validate() error {
+	for _, unit := range []string{f.unit, f.maxUnit} {
+		if _, ok := f.units.length(unit); !ok && unit != "" {
+			return newError(KindInvalidValue, 0, "unknown output unit %v", unit)
+		}
+	}
+
+	if _, ok := locales[f.language()]; !ok {
+		return newError(KindInvalidValue, 0, "unknown language %v", f.lang)
+	}
+
+	return nil
+}
+
+// value formats durations, also those of a list, in the format of f and any other value like humanValue.
+func (f formatter) value(v interface{}) string {
+	var d duration
+
+	switch n := v.(type) {
+	case list:
+		return formatList(n, f.value)
+	case time.Duration:
+		d = durationOf(n)
+	case duration:
+		d = n
+	default:
+		return humanValue(v)
+	}
+
+	switch f.format {
+	case FormatColon:
+		return formatColon(d)
+	case FormatISO8601:
+		return formatISO8601(d)
+	case FormatDecimal:
+		return f.decimal(d)
+	case FormatCompact:
+		return f.compact(d)
+	case FormatInt:
+		return f.integer(d)
+	case FormatHuman:
+		return f.human(d)
+	default:
+		return formatDuration(d)
+	}
+}
+
+// decimal formats d as decimal number of the output unit, or the largest unit that fits.
+func (f formatter) decimal(d duration) string {
+	var unit = f.unit
+	if unit == "" {
+		unit = f.largestUnit(d)
+	}
+
+	length, _ := f.units.length(unit)
+	v := new(big.Rat).Quo(d.ns, length)
+
+	if f.decimals < 0 {
+		return formatNumber(v) + unit
+	}
+
+	return v.FloatString(f.decimals) + unit
+}
+
+// integer formats d as whole number of the output unit without suffix.
+func (f formatter) integer(d duration) string {
+	var unit = f.unit
+	if unit == "" {
+		unit = "s"
+	}
+
+	length, _ := f.units.length(unit)
+
+	return truncate(new(big.Rat).Quo(d.ns, length)).String()
+}
+
+// compact formats d like formatDuration with units up to the largest unit, but leaves out zero values.
+func (f formatter) compact(d duration) string {
+	var (
+		ps     = truncate(new(big.Rat).Mul(d.ns, big.NewRat(picosecondsPerNano, 1)))
+		second = f.picoseconds("s")
+		max    = f.picoseconds(f.largest())
+		sb     = strings.Builder{}
+	)
+
+	if ps.Sign() == 0 {
+		return "0s"
+	}
+
+	if ps.Sign() < 0 {
+		sb.WriteString("-")
+		ps.Neg(ps)
+	}
+
+	var whole = ps.Cmp(second) >= 0
+	for _, unit := range []string{"w", "d", "h", "m"} {
+		length := f.picoseconds(unit)
+		if length.Cmp(max) > 0 || length.Cmp(second) <= 0 {
+			continue
+		}
+
+		q, rem := new(big.Int).QuoRem(ps, length, new(big.Int))
+		if q.Sign() > 0 {
+			sb.WriteString(q.String() + unit)
+		}
+
+		ps = rem
+	}
+
+	switch {
+	case ps.Sign() == 0:
+	case whole:
+		sb.WriteString(formatFraction(ps, second) + "s")
+	default:
+		unit := f.largestUnit(duration{ns: new(big.Rat).SetFrac(ps, big.NewInt(picosecondsPerNano))})
+		sb.WriteString(formatFraction(ps, f.picoseconds(unit)) + unit)
+	}
+
+	return sb.String()
+}
+
+// largestUnit returns the largest unit up to the largest output unit that is not larger than d, or s for zero.
+func (f formatter) largestUnit(d duration) string {
+	var (
+		abs    = new(big.Rat).Abs(d.ns)
+		max, _ = f.units.length(f.largest())
+		unit   = "s"
+	)
+
+	if abs.Sign() == 0 {
+		return unit
+	}
+
+	for _, u := range outputUnits {
+		length, _ := f.units.length(u)
+		if length.Cmp(max) > 0 || length.Cmp(abs) > 0 && u != outputUnits[0] {
+			break
+		}
+
+		unit = u
+	}
+
+	return unit
+}
+
+// largest returns the largest output unit, hours by default like time.Duration.String.
+func (f formatter) largest() string {
+	if f.maxUnit == "" {
+		return "h"
+	}
+
+	return f.maxUnit
+}
+
+// picoseconds returns the length of unit in picoseconds.
+func (f formatter) picoseconds(unit string) *big.Int {
+	length, _ := f.units.length(unit)
+
+	return truncate(new(big.Rat).Mul(length, big.NewRat(picosecondsPerNano, 1)))
+}
@@ -0,0 +1,359 @@
+package dur
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// builtin is a function that can be called in an expression. It receives the evaluated arguments of call.
+type builtin func(call *Call, args []interface{}) (interface{}, error)
+
+var builtins = map[string]builtin{
+	"round":  roundTo(RoundHalfAwayFromZero),
+	"floor":  roundTo(RoundFloor),
+	"ceil":   roundTo(RoundCeil),
+	"trunc":  roundTo(RoundTowardZero),
+	"min":    aggregate(minimum),
+	"max":    aggregate(maximum),
+	"sum":    aggregate(sum),
+	"avg":    aggregate(avg),
+	"median": aggregate(median),
+	"abs":    abs,
+	"clamp":  clampTo,
+	"count":  count,
+}
+
+// function is a function defined with def. It is evaluated in a new scope of the scope it was defined in.
+type function struct {
+	def   *Def
+	scope *Env
+}
+
+// call evaluates the arguments of n and applies the builtin or defined function it names.
+func (i *Calculator) call(n *Call) (interface{}, error) {
+	f, ok := builtins[n.Fun.Literal]
+	if !ok {
+		defined, ok := i.scope.lookupFunc(n.Fun.Literal)
+		if !ok {
+			return nil, newTokenError(KindUndefined, n.Fun, "unknown function '%v'", n.Fun.Literal)
+		}
+
+		f = i.defined(defined)
+	}
+
+	var args = make([]interface{}, len(n.Args))
+
+	for a, arg := range n.Args {
+		v, err := i.evaluate(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		args[a] = v
+	}
+
+	vr, err := f(n, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if vr, err = i.fit(vr, n.Fun); err != nil {
+		return nil, err
+	}
+
+	i.p.printCall(n.Fun.Literal, args, vr)
+
+	return vr, nil
+}
+
+// define makes the function defined by n available in the current scope.
+func (i *Calculator) define(n *Def) error {
+	if _, ok := builtins[n.Name.Literal]; ok {
+		return newTokenError(KindInvalidOperation, n.Name, "cannot redefine built-in function '%v'", n.Name.Literal)
+	}
+
+	i.scope.funcs[n.Name.Literal] = function{def: n, scope: i.scope}
+
+	return nil
+}
+
+// defined returns a builtin that checks the arguments against the parameters of f and evaluates its body.
+func (i *Calculator) defined(f function) builtin {
+	return func(call *Call, args []interface{}) (interface{}, error) {
+		if err := arity(call, len(args), len(f.def.Params)); err != nil {
+			return nil, err
+		}
+
+		if i.depth >= i.limit {
+			return nil, newTokenError(KindRecursionLimit, call.Fun, "recursion limit of %v exceeded in '%v'", i.limit, call.Fun.Literal)
+		}
+
+		scope := NewEnv(f.scope)
+
+		for a, param := range f.def.Params {
+			if param.Type.Literal != "" && param.Type.Literal != typeName(args[a]) {
+				return nil, newError(KindInvalidOperation, call.Args[a].Span().Start, "%v requires a %v as argument %v (%v), got %v", call.Fun.Literal, param.Type.Literal, a+1, param.Name.Literal, typeName(args[a]))
+			}
+
+			scope.set(param.Name.Literal, args[a])
+		}
+
+		outer := i.scope
+		i.scope = scope
+		i.depth++
+
+		defer func() {
+			i.scope = outer
+			i.depth--
+		}()
+
+		return i.evaluate(f.def.X)
+	}
+}
+
+// roundTo returns a function that rounds a duration, or each element of a list, to a multiple of a unit,
+// like round(7h52m, 15m).
+// round and trunc behave like time.Duration.Round and time.Duration.Truncate, floor and ceil round
+// toward negative and positive infinity, so negative durations are rounded correctly as well.
+func roundTo(mode RoundingMode) builtin {
+	return func(call *Call, args []interface{}) (interface{}, error) {
+		if err := arity(call, len(args), 2); err != nil {
+			return nil, err
+		}
+
+		unit, err := durationArg(call, args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if unit.ns.Sign() <= 0 {
+			return nil, newError(KindInvalidValue, call.Args[1].Span().Start, "%v requires a positive unit, got %v", call.Fun.Literal, humanValue(unit))
+		}
+
+		return mapList(args[0], func(x interface{}) (interface{}, error) {
+			d, err := durationArg(call, []interface{}{x, unit}, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			n := mode.round(new(big.Rat).Quo(d.ns, unit.ns))
+
+			return duration{ns: new(big.Rat).Mul(new(big.Rat).SetInt(n), unit.ns)}, nil
+		})
+	}
+}
+
+// aggregate returns a variadic function that reduces at least one duration or number with f, like sum(1h, 2h, 3h).
+// The elements of lists are reduced as well, like sum([1h, 2h], 3h).
+func aggregate(f func(values []*big.Rat) *big.Rat) builtin {
+	return func(call *Call, args []interface{}) (interface{}, error) {
+		if len(args) == 0 {
+			return nil, newTokenError(KindInvalidOperation, call.Fun, "%v expects at least 1 argument, got 0", call.Fun.Literal)
+		}
+
+		values, wrap, err := uniformArgs(call, args)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(values) == 0 {
+			return nil, newTokenError(KindInvalidOperation, call.Fun, "%v of an empty list is undefined", call.Fun.Literal)
+		}
+
+		return wrap(f(values)), nil
+	}
+}
+
+// count returns the number of its arguments, counting the elements of lists.
+func count(_ *Call, args []interface{}) (interface{}, error) {
+	return len(flatten(args)), nil
+}
+
+func minimum(values []*big.Rat) *big.Rat {
+	var m = values[0]
+	for _, v := range values[1:] {
+		if v.Cmp(m) < 0 {
+			m = v
+		}
+	}
+
+	return m
+}
+
+func maximum(values []*big.Rat) *big.Rat {
+	var m = values[0]
+	for _, v := range values[1:] {
+		if v.Cmp(m) > 0 {
+			m = v
+		}
+	}
+
+	return m
+}
+
+func sum(values []*big.Rat) *big.Rat {
+	var s = new(big.Rat)
+	for _, v := range values {
+		s.Add(s, v)
+	}
+
+	return s
+}
+
+func avg(values []*big.Rat) *big.Rat {
+	return new(big.Rat).Quo(sum(values), big.NewRat(int64(len(values)), 1))
+}
+
+// median returns the middle value, or the mean of both middle values if there is an even number of values.
+func median(values []*big.Rat) *big.Rat {
+	var sorted = append([]*big.Rat(nil), values...)
+
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Cmp(sorted[b]) < 0
+	})
+
+	var m = len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[m]
+	}
+
+	return avg(sorted[m-1 : m+1])
+}
+
+// abs returns the absolute value of a duration or number, or of each element of a list.
+func abs(call *Call, args []interface{}) (interface{}, error) {
+	if err := arity(call, len(args), 1); err != nil {
+		return nil, err
+	}
+
+	return mapList(args[0], func(x interface{}) (interface{}, error) {
+		values, wrap, err := uniformArgs(call, []interface{}{x})
+		if err != nil {
+			return nil, err
+		}
+
+		return wrap(new(big.Rat).Abs(values[0])), nil
+	})
+}
+
+// clampTo limits a duration or number, or each element of a list, to a range, like clamp(x, lo, hi).
+func clampTo(call *Call, args []interface{}) (interface{}, error) {
+	if err := arity(call, len(args), 3); err != nil {
+		return nil, err
+	}
+
+	for a := 1; a < len(args); a++ {
+		if _, ok := args[a].(list); ok {
+			return nil, newError(KindInvalidOperation, call.Args[a].Span().Start, "%v requires a duration or number as bound, got list", call.Fun.Literal)
+		}
+	}
+
+	return mapList(args[0], func(x interface{}) (interface{}, error) {
+		values, wrap, err := uniformArgs(call, []interface{}{x, args[1], args[2]})
+		if err != nil {
+			return nil, err
+		}
+
+		var lo, hi = values[1], values[2]
+
+		if lo.Cmp(hi) > 0 {
+			return nil, newError(KindInvalidValue, call.Args[1].Span().Start, "%v requires lower bound %v to be less than or equal to upper bound %v", call.Fun.Literal, humanValue(args[1]), humanValue(args[2]))
+		}
+
+		return wrap(maximum([]*big.Rat{lo, minimum([]*big.Rat{values[0], hi})})), nil
+	})
+}
+
+// uniformArgs returns the exact values of arguments that are either all durations or all numbers,
+// elements of lists included, and a function that turns a value back into the kind of the arguments. The number 0 is accepted
+// as duration, like in max(0, x - 8h).
+func uniformArgs(call *Call, args []interface{}) ([]*big.Rat, func(*big.Rat) interface{}, error) {
+	var (
+		values []*big.Rat
+		isDur  = false
+		wrap   = func(r *big.Rat) interface{} { return normalize(r) }
+		kind   = "numbers"
+	)
+
+	for _, arg := range flatten(args) {
+		if _, ok := arg.(duration); ok {
+			isDur = true
+		}
+	}
+
+	if isDur {
+		wrap = func(r *big.Rat) interface{} { return duration{ns: r} }
+		kind = "durations"
+	}
+
+	for a, arg := range args {
+		for _, v := range flatten([]interface{}{arg}) {
+			_, ok := v.(duration)
+
+			switch {
+			case !ok && !isScalar(v):
+				return nil, nil, newError(KindInvalidOperation, call.Args[a].Span().Start, "%v requires durations or numbers, got %v", call.Fun.Literal, typeName(v))
+			case ok != isDur && !(isDur && isZero(v)):
+				return nil, nil, newError(KindInvalidOperation, call.Args[a].Span().Start, "%v requires all arguments to be %v, got %v as argument %v", call.Fun.Literal, kind, typeName(v), a+1)
+			}
+
+			values = append(values, toRat(v))
+		}
+	}
+
+	return values, wrap, nil
+}
+
+// arity checks that call has the expected number of arguments.
+func arity(call *Call, got, want int) error {
+	if got == want {
+		return nil
+	}
+
+	return newTokenError(KindInvalidOperation, call.Fun, "%v expects %v, got %v", call.Fun.Literal, arguments(want), got)
+}
+
+func arguments(n int) string {
+	if n == 1 {
+		return "1 argument"
+	}
+
+	return fmt.Sprintf("%v arguments", n)
+}
+
+// durationArg returns the argument at index a of call if it is a duration.
+func durationArg(call *Call, args []interface{}, a int) (duration, error) {
+	if d, ok := args[a].(duration); ok {
+		return d, nil
+	}
+
+	return duration{}, newError(KindInvalidOperation, call.Args[a].Span().Start, "%v requires a duration as argument %v, got %v", call.Fun.Literal, a+1, typeName(args[a]))
+}
+
+func isTypeName(name string) bool {
+	switch name {
+	case "duration", "number", "percentage", "list", "timestamp", "clock":
+		return true
+	default:
+		return false
+	}
+}
+
+// typeName names the kind of value v for error messages and parameter types.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case duration:
+		return "duration"
+	case percent:
+		return "percentage"
+	case list:
+		return "list"
+	case timestamp:
+		return "timestamp"
+	case clock:
+		return "clock"
+	default:
+		return "number"
+	}
+}
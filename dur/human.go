@@ -0,0 +1,136 @@
+package dur
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Locale holds the words FormatHuman uses in a language, see RegisterLocale.
+type Locale struct {
+	// Units holds the names of the units w, d, h, m, s, ms, us, ns and ps.
+	Units map[string]UnitNames
+	// About precedes approximate durations like "about 3 hours".
+	About string
+	// Aliases maps further names like hrs to the units w, d, h, m, s, ms, us, ns, ps, mo and y.
+	// Like the names of Units they are accepted as units of values like 2 hrs, regardless of case.
+	Aliases map[string]string
+}
+
+// UnitNames are the singular and plural name of a unit like hour and hours.
+type UnitNames struct {
+	One   string
+	Other string
+}
+
+// locales are the locales of FormatHuman by language.
+var locales = map[string]Locale{
+	"en": {
+		Units: map[string]UnitNames{
+			"w": {"week", "weeks"}, "d": {"day", "days"}, "h": {"hour", "hours"}, "m": {"minute", "minutes"},
+			"s": {"second", "seconds"}, "ms": {"millisecond", "milliseconds"}, "us": {"microsecond", "microseconds"},
+			"ns": {"nanosecond", "nanoseconds"}, "ps": {"picosecond", "picoseconds"},
+		},
+		About: "about",
+		Aliases: map[string]string{
+			"wk": "w", "wks": "w", "hr": "h", "hrs": "h", "min": "m", "mins": "m", "sec": "s", "secs": "s",
+			"msec": "ms", "msecs": "ms", "usec": "us", "usecs": "us", "nsec": "ns", "nsecs": "ns",
+			"month": "mo", "months": "mo", "year": "y", "years": "y", "yr": "y", "yrs": "y",
+		},
+	},
+	"de": {
+		Units: map[string]UnitNames{
+			"w": {"Woche", "Wochen"}, "d": {"Tag", "Tage"}, "h": {"Stunde", "Stunden"}, "m": {"Minute", "Minuten"},
+			"s": {"Sekunde", "Sekunden"}, "ms": {"Millisekunde", "Millisekunden"}, "us": {"Mikrosekunde", "Mikrosekunden"},
+			"ns": {"Nanosekunde", "Nanosekunden"}, "ps": {"Pikosekunde", "Pikosekunden"},
+		},
+		About: "etwa",
+		Aliases: map[string]string{
+			"tagen": "d", "std": "h", "stdn": "h", "sek": "s",
+			"monat": "mo", "monate": "mo", "monaten": "mo", "jahr": "y", "jahre": "y", "jahren": "y",
+		},
+	},
+}
+
+// RegisterLocale makes a locale available to FormatHuman as language lang, see Language. Its unit names
+// are accepted in the input of all languages. English (en) and German (de) are built in.
+// It must not be called concurrently with evaluations.
+func RegisterLocale(lang string, l Locale) {
+	locales[lang] = l
+}
+
+// human formats d in words like 2 hours 30 minutes. d is first rounded to a multiple of the granularity,
+// in approximate mode to its largest unit, like about 3 hours.
+func (f formatter) human(d duration) string {
+	var (
+		locale = locales[f.language()]
+		ps     = roundMultiple(truncate(new(big.Rat).Mul(d.ns, big.NewRat(picosecondsPerNano, 1))), f.granularity)
+		sb     = strings.Builder{}
+		parts  []string
+	)
+
+	if f.approximate && ps.Sign() != 0 {
+		exact := ps
+		unit := f.largestUnit(duration{ns: new(big.Rat).SetFrac(ps, big.NewInt(picosecondsPerNano))})
+
+		if ps = roundMultiple(exact, f.picoseconds(unit)); ps.Cmp(exact) != 0 {
+			sb.WriteString(locale.About + " ")
+		}
+	}
+
+	if ps.Sign() < 0 {
+		sb.WriteString("-")
+		ps.Neg(ps)
+	}
+
+	var max = f.picoseconds(f.largest())
+	for _, unit := range []string{"w", "d", "h", "m", "s", "ms", "us", "ns", "ps"} {
+		length := f.picoseconds(unit)
+		if length.Cmp(max) > 0 {
+			continue
+		}
+
+		q, rem := new(big.Int).QuoRem(ps, length, new(big.Int))
+		if q.Sign() > 0 {
+			parts = append(parts, q.String()+" "+locale.plural(unit, q))
+		}
+
+		ps = rem
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, "0 "+locale.plural("s", ps))
+	}
+
+	sb.WriteString(strings.Join(parts, " "))
+
+	return sb.String()
+}
+
+// roundMultiple rounds v to a multiple of multiple, halfway values away from zero. v is returned as is
+// if multiple is not positive.
+func roundMultiple(v, multiple *big.Int) *big.Int {
+	if multiple == nil || multiple.Sign() <= 0 {
+		return v
+	}
+
+	q := RoundHalfAwayFromZero.round(new(big.Rat).SetFrac(v, multiple))
+
+	return q.Mul(q, multiple)
+}
+
+// language returns the language of FormatHuman, English by default.
+func (f formatter) language() string {
+	if f.lang == "" {
+		return "en"
+	}
+
+	return f.lang
+}
+
+func (l Locale) plural(unit string, n *big.Int) string {
+	if n.Cmp(big.NewInt(1)) == 0 {
+		return l.Units[unit].One
+	}
+
+	return l.Units[unit].Other
+}
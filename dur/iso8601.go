@@ -0,0 +1,144 @@
+package dur
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// isoPattern matches ISO 8601 durations like PT1H30M, P3DT4H or PT0.5S. Only the designators
+// in the order of ISO 8601 are matched, the number of each is captured.
+var isoPattern = regexp.MustCompile(`^P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?` +
+	`(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?`)
+
+// isoDesignators are the designators captured by isoPattern, the length of years and months depends on the anchor date.
+var isoDesignators = []struct {
+	name   string
+	length time.Duration
+}{
+	{name: "Y"}, {name: "M"}, {name: "W", length: calendarWeek}, {name: "D", length: calendarDay},
+	{name: "H", length: time.Hour}, {name: "M", length: time.Minute}, {name: "S", length: time.Second},
+}
+
+// findISO8601 returns the ISO 8601 duration at the beginning of s. It needs at least one designator
+// and must not be followed by a letter or digit, so identifiers like PTO are not mistaken for durations.
+func findISO8601(s string) string {
+	lit := isoPattern.FindString(s)
+	if !strings.ContainsAny(lit, "0123456789") || strings.HasSuffix(lit, "T") {
+		return ""
+	}
+
+	if len(s) > len(lit) && (isLetter(s[len(lit)]) || isDigit(s[len(lit)])) {
+		return ""
+	}
+
+	return lit
+}
+
+// isISO8601 reports whether lit is a duration in ISO 8601 format.
+func isISO8601(lit string) bool {
+	return strings.HasPrefix(lit, "P")
+}
+
+// parseISO8601 parses an ISO 8601 duration like PT1H30M or P3DT4H exactly. Weeks and days are 168h and 24h.
+// Years and months are resolved from the anchor date from like the units y and mo, the date they and
+// the rest of the duration lead to is returned as to. It is zero if lit has neither years nor months.
+func parseISO8601(lit string, from time.Time) (duration, time.Time, error) {
+	months, ns, err := splitISO8601(lit, from)
+	if err != nil {
+		return duration{}, time.Time{}, err
+	}
+
+	if months == 0 {
+		return duration{ns: ns}, time.Time{}, nil
+	}
+
+	to := addMonths(from, months)
+	d := durationBetween(from, to)
+
+	return duration{ns: d.ns.Add(d.ns, ns)}, to.Add(time.Duration(truncate(ns).Int64())), nil
+}
+
+// splitISO8601 splits an ISO 8601 duration into its years and months, counted in months, and the exact rest.
+// Years and months require an anchor date from.
+func splitISO8601(lit string, from time.Time) (int, *big.Rat, error) {
+	var (
+		numbers = isoPattern.FindStringSubmatch(lit)[1:]
+		ns      = new(big.Rat)
+		months  int
+	)
+
+	for n, number := range numbers {
+		if number == "" {
+			continue
+		}
+
+		v, err := parseDecimal(number)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid duration %v: %v", lit, err)
+		}
+
+		designator := isoDesignators[n]
+		if designator.length != 0 {
+			ns.Add(ns, v.Mul(v, toRat(designator.length)))
+
+			continue
+		}
+
+		switch {
+		case from.IsZero():
+			return 0, nil, fmt.Errorf("designator %v requires an anchor date", designator.name)
+		case !v.IsInt() || !v.Num().IsInt64():
+			return 0, nil, fmt.Errorf("floating point values for designator %v is not supported", designator.name)
+		case designator.name == "Y":
+			months += 12 * int(v.Num().Int64())
+		default:
+			months += int(v.Num().Int64())
+		}
+	}
+
+	return months, ns, nil
+}
+
+// formatISO8601 formats d as ISO 8601 duration of hours, minutes and seconds like PT1H30M or PT0.5S with a precision
+// of picoseconds, like Duration.toString in Java, but with a leading sign for negative durations.
+func formatISO8601(d duration) string {
+	var (
+		ps   = truncate(new(big.Rat).Mul(d.ns, big.NewRat(picosecondsPerNano, 1)))
+		sign = ""
+	)
+
+	if ps.Sign() == 0 {
+		return "PT0S"
+	}
+
+	if ps.Sign() < 0 {
+		sign = "-"
+		ps.Neg(ps)
+	}
+
+	var (
+		second            = big.NewInt(int64(time.Second) * picosecondsPerNano)
+		minutes, fraction = new(big.Int).QuoRem(ps, new(big.Int).Mul(second, big.NewInt(60)), new(big.Int))
+		hours, _          = new(big.Int).QuoRem(minutes, big.NewInt(60), minutes)
+		sb                = strings.Builder{}
+	)
+
+	sb.WriteString(sign + "PT")
+
+	if hours.Sign() > 0 {
+		sb.WriteString(hours.String() + "H")
+	}
+
+	if minutes.Sign() > 0 {
+		sb.WriteString(minutes.String() + "M")
+	}
+
+	if fraction.Sign() > 0 {
+		sb.WriteString(formatFraction(fraction, second) + "S")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+package dur
+
+import (
+	"strings"
+	"time"
+)
+
+// list is a list of values like [1h, 2h30m, 45m]. Operators are applied element-wise, see elementWise.
+type list []interface{}
+
+// elementWise applies op to each pair of elements of two lists of the same length. If only one operand
+// is a list, the other one is applied to each of its elements.
+func elementWise(op Token, v1, v2 interface{}) (list, error) {
+	l1, isList1 := v1.(list)
+	l2, isList2 := v2.(list)
+
+	if isList1 && isList2 && len(l1) != len(l2) {
+		return nil, newTokenError(KindInvalidOperation, op, "cannot apply '%v' to lists of length %v and %v", opSymbol(op.Type), len(l1), len(l2))
+	}
+
+	var n = len(l1)
+	if isList2 {
+		n = len(l2)
+	}
+
+	var vr = make(list, n)
+
+	for e := range vr {
+		var a, b = v1, v2
+
+		if isList1 {
+			a = l1[e]
+		}
+
+		if isList2 {
+			b = l2[e]
+		}
+
+		v, err := operate(op, a, b)
+		if err != nil {
+			return nil, err
+		}
+
+		vr[e] = v
+	}
+
+	return vr, nil
+}
+
+// mapList applies f to v, or to each element if v is a list.
+func mapList(v interface{}, f func(e interface{}) (interface{}, error)) (interface{}, error) {
+	l, ok := v.(list)
+	if !ok {
+		return f(v)
+	}
+
+	var vr = make(list, len(l))
+
+	for e := range l {
+		v, err := mapList(l[e], f)
+		if err != nil {
+			return nil, err
+		}
+
+		vr[e] = v
+	}
+
+	return vr, nil
+}
+
+// flatten returns the elements of lists in values, other values as they are.
+func flatten(values []interface{}) []interface{} {
+	var flat []interface{}
+
+	for _, v := range values {
+		if l, ok := v.(list); ok {
+			flat = append(flat, flatten(l)...)
+		} else {
+			flat = append(flat, v)
+		}
+	}
+
+	return flat
+}
+
+// exact turns rounded durations and timestamps of a Result back into exact values.
+func exact(v interface{}) interface{} {
+	vr, _ := mapList(v, func(e interface{}) (interface{}, error) {
+		if d, ok := e.(time.Duration); ok {
+			return durationOf(d), nil
+		}
+
+		if t, ok := e.(time.Time); ok {
+			return timestampOf(t), nil
+		}
+
+		return e, nil
+	})
+
+	return vr
+}
+
+func formatList(l list, format func(interface{}) string) string {
+	var elems = make([]string, len(l))
+	for e, v := range l {
+		elems[e] = format(v)
+	}
+
+	return "[" + strings.Join(elems, ", ") + "]"
+}
@@ -0,0 +1,158 @@
+package dur
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func parseDecimal(lit string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.ReplaceAll(lit, ",", "."))
+	if !ok {
+		return nil, &strconv.NumError{Func: "ParseDecimal", Num: lit, Err: strconv.ErrSyntax}
+	}
+
+	return r, nil
+}
+
+// isScalar reports whether v is a number without unit. Integers stay int as long as only integers are added,
+// subtracted or multiplied, decimals like 1,5 and divisions yield an exact *big.Rat.
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case int, *big.Rat:
+		return true
+	default:
+		return false
+	}
+}
+
+func isZero(v interface{}) bool {
+	switch n := v.(type) {
+	case int:
+		return n == 0
+	case *big.Rat:
+		return n.Sign() == 0
+	case duration:
+		return n.ns.Sign() == 0
+	default:
+		return false
+	}
+}
+
+func toRat(v interface{}) *big.Rat {
+	switch n := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(n))
+	case *big.Rat:
+		return n
+	case duration:
+		return n.ns
+	case time.Duration:
+		return new(big.Rat).SetInt64(int64(n))
+	default:
+		return new(big.Rat)
+	}
+}
+
+// normalize returns r as int if it is a whole number that fits, so it can be used where integers are required.
+func normalize(r *big.Rat) interface{} {
+	if r.IsInt() && r.Num().IsInt64() {
+		if n := r.Num().Int64(); int64(int(n)) == n {
+			return int(n)
+		}
+	}
+
+	return r
+}
+
+// RoundingMode defines how an exact result is rounded to whole nanoseconds.
+type RoundingMode int
+
+const (
+	// RoundTowardZero truncates fractions of nanoseconds like integer division of time.Duration does.
+	RoundTowardZero RoundingMode = iota
+	// RoundHalfAwayFromZero rounds to the nearest nanosecond, halfway values away from zero.
+	RoundHalfAwayFromZero
+	// RoundHalfEven rounds to the nearest nanosecond, halfway values to the even one.
+	RoundHalfEven
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeil rounds toward positive infinity.
+	RoundCeil
+)
+
+// round rounds r to a whole number.
+func (m RoundingMode) round(r *big.Rat) *big.Int {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+
+	var (
+		sign = int64(r.Sign())
+		half = new(big.Int).Abs(rem)
+		away bool
+	)
+
+	switch half.Lsh(half, 1).Cmp(r.Denom()) {
+	case 1:
+		away = m != RoundTowardZero
+	case 0:
+		away = m == RoundHalfAwayFromZero || m == RoundHalfEven && q.Bit(0) == 1
+	}
+
+	switch m {
+	case RoundFloor:
+		away = sign < 0
+	case RoundCeil:
+		away = sign > 0
+	}
+
+	if away {
+		q.Add(q, big.NewInt(sign))
+	}
+
+	return q
+}
+
+// truncate rounds r toward zero.
+func truncate(r *big.Rat) *big.Int {
+	return RoundTowardZero.round(r)
+}
+
+// toDuration rounds d to whole nanoseconds.
+func (m RoundingMode) toDuration(d duration) time.Duration {
+	return time.Duration(m.round(d.ns).Int64())
+}
+
+// formatNumber formats a scalar as integer or decimal number. Decimals are limited to 9 fractional digits.
+func formatNumber(v interface{}) string {
+	r := toRat(v)
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	s := strings.TrimRight(r.FloatString(9), "0")
+
+	return strings.TrimSuffix(s, ".")
+}
+
+// percent is a percentage like 20%, r holds its fraction 0.2. It is applied to the value it follows,
+// see applyPercent.
+type percent struct {
+	r *big.Rat
+}
+
+func parsePercent(lit string) (percent, error) {
+	r, err := parseDecimal(strings.TrimSuffix(lit, "%"))
+	if err != nil {
+		return percent{}, err
+	}
+
+	return percent{r: r.Quo(r, big.NewRat(100, 1))}, nil
+}
+
+func (p percent) String() string {
+	return formatNumber(new(big.Rat).Mul(p.r, big.NewRat(100, 1))) + "%"
+}
@@ -0,0 +1,177 @@
+package dur
+
+import "time"
+
+type options struct {
+	p                printer
+	legacyPrecedence bool
+	units            units
+	from             time.Time
+	divMod           bool
+	rounding         RoundingMode
+	saturate         bool
+	big              bool
+	env              *Env
+	recursionLimit   int
+	colonDurations   bool
+	format           OutputFormat
+	outputUnit       string
+	decimals         int
+	maxUnit          string
+	lang             string
+	granularity      time.Duration
+	approximate      bool
+}
+
+type Option func(o *options)
+
+const defaultRecursionLimit = 100
+
+func newOptions(opts []Option) options {
+	var o options
+
+	DiscardPrinter(&o)
+	CalendarDays(&o)
+	RecursionLimit(defaultRecursionLimit)(&o)
+	Decimals(-1)(&o)
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func DiscardPrinter(o *options) {
+	o.p = discardPrinter{}
+}
+func HumanReadablePrinter(o *options) {
+	o.p = humanReadablePrinter{}
+}
+func NanoPrinter(o *options) {
+	o.p = nanoPrinter{}
+}
+
+// LegacyPrecedence evaluates all operators strictly from left to right, so 10m+20m*2 yields 1h.
+func LegacyPrecedence(o *options) {
+	o.legacyPrecedence = true
+}
+
+// CalendarDays interprets the units d and w as 24h and 168h. This is the default.
+func CalendarDays(o *options) {
+	o.units.day, o.units.week = calendarDay, calendarWeek
+}
+
+// WorkDays interprets the units d and w as a work day of 8h and a work week of 40h.
+func WorkDays(o *options) {
+	o.units.day, o.units.week = workDay, workWeek
+}
+
+// DayLength interprets the units d and w as the given lengths of a day and a week.
+func DayLength(day, week time.Duration) Option {
+	return func(o *options) {
+		o.units.day, o.units.week = day, week
+	}
+}
+
+// From sets the anchor date from which the units mo and y are resolved.
+func From(t time.Time) Option {
+	return func(o *options) {
+		o.from = t
+	}
+}
+
+// DivMod evaluates a division at the root of the expression into its integral quotient and the remainder,
+// see Result.Remainder.
+func DivMod(o *options) {
+	o.divMod = true
+}
+
+// Rounding sets how the exact result is rounded to whole nanoseconds. The default is RoundTowardZero.
+func Rounding(mode RoundingMode) Option {
+	return func(o *options) {
+		o.rounding = mode
+	}
+}
+
+// Saturate clamps durations that exceed the range of time.Duration to its minimum or maximum
+// instead of reporting an overflow.
+func Saturate(o *options) {
+	o.saturate = true
+}
+
+// Environment makes the variables of env available to expressions. Assignments are made in a new scope,
+// so env is not modified.
+func Environment(env *Env) Option {
+	return func(o *options) {
+		o.env = env
+	}
+}
+
+// RecursionLimit limits how deep calls of functions defined with def may be nested. The default is 100.
+func RecursionLimit(n int) Option {
+	return func(o *options) {
+		o.recursionLimit = n
+	}
+}
+
+// ColonDurations reads all values in colon notation like 1:30 as durations instead of times of day.
+// Values with days or fractions of seconds like 2.04:30:00 or 1:23:45.5 are durations anyway.
+func ColonDurations(o *options) {
+	o.colonDurations = true
+}
+
+// Output sets how durations of the result are formatted by Result.String.
+func Output(f OutputFormat) Option {
+	return func(o *options) {
+		o.format = f
+	}
+}
+
+// OutputUnit sets the unit like h or s in which FormatDecimal and FormatInt format durations.
+func OutputUnit(unit string) Option {
+	return func(o *options) {
+		o.outputUnit = unit
+	}
+}
+
+// Decimals sets the number of fractional digits of FormatDecimal, the last one is rounded half away from zero.
+// By default, as many digits as needed are formatted, up to 9.
+func Decimals(n int) Option {
+	return func(o *options) {
+		o.decimals = n
+	}
+}
+
+// MaxUnit sets the largest unit like d or w that FormatCompact and FormatDecimal use. The default is h.
+func MaxUnit(unit string) Option {
+	return func(o *options) {
+		o.maxUnit = unit
+	}
+}
+
+// Language sets the language of FormatHuman like en or de, see RegisterLocale. The default is en.
+func Language(lang string) Option {
+	return func(o *options) {
+		o.lang = lang
+	}
+}
+
+// Granularity rounds durations formatted with FormatHuman to a multiple of d, like 1m for 2 hours 30 minutes
+// instead of 2 hours 29 minutes 40 seconds. Halfway values are rounded away from zero.
+func Granularity(d time.Duration) Option {
+	return func(o *options) {
+		o.granularity = d
+	}
+}
+
+// Approximate rounds durations formatted with FormatHuman to their largest unit, like about 3 hours.
+func Approximate(o *options) {
+	o.approximate = true
+}
+
+// BigPrecision evaluates durations without the range limit of time.Duration and rounds the result
+// to picoseconds instead of nanoseconds. Use Result.Nanoseconds to get results that exceed time.Duration.
+func BigPrecision(o *options) {
+	o.big = true
+}
@@ -0,0 +1,489 @@
+package dur
+
+// parser builds a syntax tree from tokens. The root of an empty input is nil.
+type parser struct {
+	tokens []Token
+	pos    int
+}
+
+func parse(tokens []Token, legacy bool) (Node, error) {
+	p := &parser{tokens: tokens}
+
+	if legacy {
+		return p.statements(p.sequence)
+	}
+
+	return p.statements(p.expression)
+}
+
+// statements parses statements separated by semicolons or newlines, empty statements are skipped.
+// A single statement is returned as it is, multiple statements as *Statements.
+func (p *parser) statements(inner func(isEnd func() bool) (Node, error)) (Node, error) {
+	var list []Node
+
+	for !p.eof() {
+		if p.tokenTypeEquals(TypeSemicolon) {
+			p.next()
+
+			continue
+		}
+
+		x, err := p.statement(inner)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, x)
+	}
+
+	switch len(list) {
+	case 0:
+		return nil, nil
+	case 1:
+		return list[0], nil
+	default:
+		return &Statements{List: list}, nil
+	}
+}
+
+// statement parses a function definition, an assignment like rate = 45m or an expression.
+func (p *parser) statement(inner func(isEnd func() bool) (Node, error)) (Node, error) {
+	if p.tokenTypeEquals(TypeDef) {
+		return p.def(inner)
+	}
+
+	if !p.tokenTypeEquals(TypeIdent) || p.peek().Type != TypeAssign {
+		return inner(p.statementEnd)
+	}
+
+	name := p.next()
+	p.next()
+
+	x, err := inner(p.statementEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if x == nil {
+		return nil, p.unexpectedToken()
+	}
+
+	return &Assign{Name: name, X: x}, nil
+}
+
+// expression parses additive operations until isEnd reports true.
+// Operands that follow each other without an operator are added.
+func (p *parser) expression(isEnd func() bool) (Node, error) {
+	if isEnd() {
+		return nil, nil
+	}
+
+	x, err := p.term(isEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	for !isEnd() {
+		var (
+			op       = Token{Type: TypePlus, Pos: p.token().Pos}
+			implicit = true
+		)
+
+		if p.isAdditiveOperator() {
+			op, implicit = p.next(), false
+			if isEnd() {
+				break
+			}
+		}
+
+		y, err := p.term(isEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		x = &Binary{Op: op, X: x, Y: y, Implicit: implicit}
+	}
+
+	return x, nil
+}
+
+// term parses multiplicative operations, which bind tighter than additive ones.
+func (p *parser) term(isEnd func() bool) (Node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for !isEnd() && p.isMultiplicativeOperator() {
+		op := p.next()
+		if isEnd() {
+			break
+		}
+
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		x = &Binary{Op: op, X: x, Y: y}
+	}
+
+	return x, nil
+}
+
+// unary parses an optional sign, which binds tightest.
+func (p *parser) unary() (Node, error) {
+	if !p.isAdditiveOperator() {
+		return p.primary()
+	}
+
+	op := p.next()
+
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Unary{Op: op, X: x}, nil
+}
+
+// primary parses a group or a value. Consecutive durations like 1h30m form a single value.
+func (p *parser) primary() (Node, error) {
+	switch p.tokenType() {
+	case TypeParenOpen:
+		return p.group(p.expression)
+	case TypeDuration:
+		var x Node = p.literal()
+
+		for p.tokenTypeEquals(TypeDuration) {
+			op := Token{Type: TypePlus, Pos: p.token().Pos}
+			x = &Binary{Op: op, X: x, Y: p.literal(), Implicit: true}
+		}
+
+		return x, nil
+	case TypeInteger, TypeDecimal, TypePercent, TypeTimestamp:
+		return p.literal(), nil
+	case TypeClock:
+		return p.clock()
+	case TypeIdent:
+		return p.ident(p.expression)
+	case TypeBrackOpen:
+		return p.list(p.expression)
+	default:
+		return nil, p.unexpectedToken()
+	}
+}
+
+// sequence parses all operations strictly from left to right. It is used when legacy precedence is enabled.
+func (p *parser) sequence(isEnd func() bool) (Node, error) {
+	if isEnd() {
+		return nil, nil
+	}
+
+	x, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for !isEnd() {
+		var (
+			op       = Token{Type: TypePlus, Pos: p.token().Pos}
+			implicit = true
+		)
+
+		if p.isOperator() {
+			op, implicit = p.next(), false
+			if isEnd() {
+				break
+			}
+		}
+
+		y, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+
+		x = &Binary{Op: op, X: x, Y: y, Implicit: implicit}
+	}
+
+	return x, nil
+}
+
+// operand parses a single, optionally signed value or group for legacy precedence.
+func (p *parser) operand() (Node, error) {
+	var op *Token
+
+	if p.isAdditiveOperator() {
+		tok := p.next()
+		op = &tok
+	}
+
+	var (
+		x   Node
+		err error
+	)
+
+	switch p.tokenType() {
+	case TypeParenOpen:
+		x, err = p.group(p.sequence)
+	case TypeDuration, TypeInteger, TypeDecimal, TypePercent, TypeTimestamp:
+		x = p.literal()
+	case TypeClock:
+		x, err = p.clock()
+	case TypeIdent:
+		x, err = p.ident(p.sequence)
+	case TypeBrackOpen:
+		x, err = p.list(p.sequence)
+	default:
+		err = p.unexpectedToken()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if op != nil {
+		return &Unary{Op: *op, X: x}, nil
+	}
+
+	return x, nil
+}
+
+func (p *parser) group(inner func(isEnd func() bool) (Node, error)) (Node, error) {
+	lparen := p.next().Pos
+
+	x, err := inner(p.closingParen)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Group{Lparen: lparen, Rparen: p.next().Pos, X: x}, nil
+}
+
+// def parses a function definition like def overtime(x: duration) = max(0, x - 8h).
+func (p *parser) def(inner func(isEnd func() bool) (Node, error)) (Node, error) {
+	var pos = p.next().Pos
+
+	if !p.tokenTypeEquals(TypeIdent) {
+		return nil, p.unexpectedToken()
+	}
+
+	var (
+		name   = p.next()
+		params []Param
+		seen   = map[string]bool{}
+	)
+
+	if !p.tokenTypeEquals(TypeParenOpen) {
+		return nil, p.unexpectedToken()
+	}
+
+	for p.next(); !p.closingParen(); {
+		if !p.tokenTypeEquals(TypeIdent) {
+			return nil, p.unexpectedToken()
+		}
+
+		param := Param{Name: p.next()}
+		if seen[param.Name.Literal] {
+			return nil, newTokenError(KindUnexpectedToken, param.Name, "duplicate parameter '%v'", param.Name.Literal)
+		}
+
+		seen[param.Name.Literal] = true
+
+		if p.tokenTypeEquals(TypeColon) {
+			p.next()
+
+			if !p.tokenTypeEquals(TypeIdent) {
+				return nil, p.unexpectedToken()
+			}
+
+			if param.Type = p.next(); !isTypeName(param.Type.Literal) {
+				return nil, newTokenError(KindUnexpectedToken, param.Type, "unknown type '%v', want duration, number, percentage, list, timestamp or clock", param.Type.Literal)
+			}
+		}
+
+		params = append(params, param)
+
+		if p.tokenTypeEquals(TypeComma) && p.peek().Type != TypeParenClose {
+			p.next()
+		} else if !p.closingParen() {
+			return nil, p.unexpectedToken()
+		}
+	}
+
+	p.next()
+
+	if !p.tokenTypeEquals(TypeAssign) {
+		return nil, p.unexpectedToken()
+	}
+
+	p.next()
+
+	x, err := inner(p.statementEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if x == nil {
+		return nil, p.unexpectedToken()
+	}
+
+	return &Def{Pos: pos, Name: name, Params: params, X: x}, nil
+}
+
+// ident parses a function call or a reference to a variable.
+func (p *parser) ident(inner func(isEnd func() bool) (Node, error)) (Node, error) {
+	if p.peek().Type == TypeParenOpen {
+		return p.call(inner)
+	}
+
+	return &Ident{Token: p.next()}, nil
+}
+
+// call parses a function call like ceil(7h52m, 15m). Each argument is parsed by inner.
+func (p *parser) call(inner func(isEnd func() bool) (Node, error)) (Node, error) {
+	var (
+		fun    = p.next()
+		lparen = p.next().Pos
+	)
+
+	args, err := p.elements(inner, TypeParenClose)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Call{Fun: fun, Lparen: lparen, Args: args, Rparen: p.next().Pos}, nil
+}
+
+// list parses a list literal like [1h, 2h30m, 45m]. Each element is parsed by inner.
+func (p *parser) list(inner func(isEnd func() bool) (Node, error)) (Node, error) {
+	lbrack := p.next().Pos
+
+	elems, err := p.elements(inner, TypeBrackClose)
+	if err != nil {
+		return nil, err
+	}
+
+	return &List{Lbrack: lbrack, Elems: elems, Rbrack: p.next().Pos}, nil
+}
+
+// elements parses comma separated expressions up to the closing token, which is not consumed.
+func (p *parser) elements(inner func(isEnd func() bool) (Node, error), closing TokenType) ([]Node, error) {
+	var (
+		elems []Node
+		isEnd = func() bool {
+			return p.tokenTypeEquals(closing) || p.tokenTypeEquals(TypeComma)
+		}
+	)
+
+	for !p.tokenTypeEquals(closing) {
+		x, err := inner(isEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		if x == nil {
+			return nil, p.unexpectedToken()
+		}
+
+		elems = append(elems, x)
+
+		if p.tokenTypeEquals(TypeComma) {
+			p.next()
+
+			if p.tokenTypeEquals(closing) {
+				return nil, p.unexpectedToken()
+			}
+		}
+	}
+
+	return elems, nil
+}
+
+// clock parses a time of day or a range of two times of day like 09:15-17:45 or 09:15..17:45.
+// A range binds tighter than any operator.
+func (p *parser) clock() (Node, error) {
+	from := p.literal()
+
+	if !p.tokenTypeEquals(TypeMinus) && !p.tokenTypeEquals(TypeRange) {
+		return from, nil
+	}
+
+	if p.peek().Type != TypeClock {
+		if p.tokenTypeEquals(TypeRange) {
+			p.next()
+
+			return nil, p.unexpectedToken()
+		}
+
+		return from, nil
+	}
+
+	return &Range{From: from, Op: p.next(), To: p.literal()}, nil
+}
+
+func (p *parser) literal() *Literal {
+	return &Literal{Token: p.next()}
+}
+
+// next returns the current token and advances to the next one.
+func (p *parser) next() Token {
+	var tok = p.token()
+
+	p.pos++
+
+	return tok
+}
+
+func (p *parser) unexpectedToken() error {
+	if p.tokenTypeEquals(TypeParenClose) {
+		return newTokenError(KindUnexpectedToken, p.token(), "unexpected closing parenthesis")
+	}
+
+	return newTokenError(KindUnexpectedToken, p.token(), "unexpected token '%v'", p.tokenType())
+}
+
+func (p *parser) eof() bool {
+	return p.tokenTypeEquals(TypeEOF)
+}
+
+func (p *parser) closingParen() bool {
+	return p.tokenTypeEquals(TypeParenClose)
+}
+
+func (p *parser) statementEnd() bool {
+	return p.eof() || p.tokenTypeEquals(TypeSemicolon)
+}
+
+func (p *parser) tokenTypeEquals(tokenType TokenType) bool {
+	return p.tokenType() == tokenType
+}
+
+func (p *parser) tokenType() TokenType {
+	return p.token().Type
+}
+
+func (p *parser) token() Token {
+	return p.tokens[p.pos]
+}
+
+// peek returns the token after the current one.
+func (p *parser) peek() Token {
+	if p.pos+1 >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.pos+1]
+}
+
+func (p *parser) isOperator() bool {
+	return p.isAdditiveOperator() || p.isMultiplicativeOperator()
+}
+
+func (p *parser) isAdditiveOperator() bool {
+	return p.tokenTypeEquals(TypeMinus) || p.tokenTypeEquals(TypePlus)
+}
+
+func (p *parser) isMultiplicativeOperator() bool {
+	return p.tokenTypeEquals(TypeMultiply) || p.tokenTypeEquals(TypeDivide) || p.tokenTypeEquals(TypeModulo)
+}
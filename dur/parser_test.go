@@ -0,0 +1,129 @@
+package dur_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Oppodelldog/dur/dur"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		opts  []dur.Option
+		want  string
+		shape string
+		span  dur.Span
+		empty bool
+	}
+
+	tests := []testCase{
+		{name: "empty", input: "", empty: true},
+		{name: "literal", input: "1h", want: "1h", shape: "*dur.Literal", span: dur.Span{Start: 0, End: 2}},
+		{name: "compound literal", input: "1h30m", want: "1h30m", shape: "*dur.Binary(*dur.Literal,*dur.Literal)", span: dur.Span{Start: 0, End: 5}},
+		{name: "precedence", input: "10m+20m*2", want: "10m + 20m * 2", shape: "*dur.Binary(*dur.Literal,*dur.Binary(*dur.Literal,*dur.Literal))", span: dur.Span{Start: 0, End: 9}},
+		{name: "legacy precedence", input: "10m+20m*2", opts: []dur.Option{dur.LegacyPrecedence}, want: "10m + 20m * 2", shape: "*dur.Binary(*dur.Binary(*dur.Literal,*dur.Literal),*dur.Literal)", span: dur.Span{Start: 0, End: 9}},
+		{name: "unary", input: " -1h", want: "-1h", shape: "*dur.Unary(*dur.Literal)", span: dur.Span{Start: 1, End: 4}},
+		{name: "group", input: "2*(1h - 30m)", want: "2 * (1h - 30m)", shape: "*dur.Binary(*dur.Literal,*dur.Group(*dur.Binary(*dur.Literal,*dur.Literal)))", span: dur.Span{Start: 0, End: 12}},
+		{name: "call", input: "ceil(7h52m,15m)", want: "ceil(7h52m, 15m)", shape: "*dur.Call(*dur.Binary(*dur.Literal,*dur.Literal),*dur.Literal)", span: dur.Span{Start: 0, End: 15}},
+		{name: "nested call", input: "2*floor( (1h), ceil(1m,1s) )", want: "2 * floor((1h), ceil(1m, 1s))", shape: "*dur.Binary(*dur.Literal,*dur.Call(*dur.Group(*dur.Literal),*dur.Call(*dur.Literal,*dur.Literal)))", span: dur.Span{Start: 0, End: 28}},
+		{name: "variable", input: "rate*7", want: "rate * 7", shape: "*dur.Binary(*dur.Ident,*dur.Literal)", span: dur.Span{Start: 0, End: 6}},
+		{name: "assignment", input: "rate = 45m", want: "rate = 45m", shape: "*dur.Assign(*dur.Literal)", span: dur.Span{Start: 0, End: 10}},
+		{name: "statements", input: "rate = 45m;\nrate*7;", want: "rate = 45m; rate * 7", shape: "*dur.Statements(*dur.Assign(*dur.Literal),*dur.Binary(*dur.Ident,*dur.Literal))", span: dur.Span{Start: 0, End: 18}},
+		{name: "empty statements", input: " ; ;", empty: true},
+		{name: "definition", input: "def f(x:duration,n)=x*n", want: "def f(x: duration, n) = x * n", shape: "*dur.Def(*dur.Binary(*dur.Ident,*dur.Ident))", span: dur.Span{Start: 0, End: 23}},
+		{name: "list", input: "[1h,2h30m]*2", want: "[1h, 2h30m] * 2", shape: "*dur.Binary(*dur.List(*dur.Literal,*dur.Binary(*dur.Literal,*dur.Literal)),*dur.Literal)", span: dur.Span{Start: 0, End: 12}},
+		{name: "empty list", input: "[]", want: "[]", shape: "*dur.List", span: dur.Span{Start: 0, End: 2}},
+		{name: "clock range", input: "09:15 - 17:45", want: "09:15-17:45", shape: "*dur.Range(*dur.Literal,*dur.Literal)", span: dur.Span{Start: 0, End: 13}},
+		{name: "clock range with dots", input: "22:00..06:00+30m", want: "22:00..06:00 + 30m", shape: "*dur.Binary(*dur.Range(*dur.Literal,*dur.Literal),*dur.Literal)", span: dur.Span{Start: 0, End: 16}},
+		{name: "clock time minus duration", input: "17:45-30m", want: "17:45 - 30m", shape: "*dur.Binary(*dur.Literal,*dur.Literal)", span: dur.Span{Start: 0, End: 9}},
+		{name: "colon durations", input: "1:30-0:45", opts: []dur.Option{dur.ColonDurations}, want: "1:30 - 0:45", shape: "*dur.Binary(*dur.Literal,*dur.Literal)", span: dur.Span{Start: 0, End: 9}},
+		{name: "unit names", input: "1 hour 30 min", want: "1 hour30 min", shape: "*dur.Binary(*dur.Literal,*dur.Literal)", span: dur.Span{Start: 0, End: 13}},
+		{name: "empty group", input: "1h()", want: "1h ()", shape: "*dur.Binary(*dur.Literal,*dur.Group)", span: dur.Span{Start: 0, End: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := dur.Parse(tt.input, tt.opts...)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			root := expr.Root()
+			if tt.empty {
+				if root != nil {
+					t.Errorf("Root() = %v, want nil", root)
+				}
+
+				return
+			}
+
+			if got := root.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+
+			if got := shape(root); got != tt.shape {
+				t.Errorf("shape = %v, want %v", got, tt.shape)
+			}
+
+			if got := root.Span(); !reflect.DeepEqual(got, tt.span) {
+				t.Errorf("Span() = %v, want %v", got, tt.span)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{"(", "1h)", "--1h", "1h(1h", "a =", "1h = 2h", "(a; b)", "ceil(1h", "ceil(1h,", "ceil(,)", ",", "09:00..", "09:00..1h"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := dur.Parse(input); err == nil {
+				t.Errorf("Parse(%q) expected error", input)
+			}
+		})
+	}
+}
+
+func shape(node dur.Node) string {
+	var children []dur.Node
+
+	switch n := node.(type) {
+	case *dur.Unary:
+		children = []dur.Node{n.X}
+	case *dur.Binary:
+		children = []dur.Node{n.X, n.Y}
+	case *dur.Group:
+		if n.X != nil {
+			children = []dur.Node{n.X}
+		}
+	case *dur.Call:
+		children = n.Args
+	case *dur.List:
+		children = n.Elems
+	case *dur.Range:
+		children = []dur.Node{n.From, n.To}
+	case *dur.Assign:
+		children = []dur.Node{n.X}
+	case *dur.Def:
+		children = []dur.Node{n.X}
+	case *dur.Statements:
+		children = n.List
+	}
+
+	s := reflect.TypeOf(node).String()
+	for i, c := range children {
+		if i == 0 {
+			s += "("
+		} else {
+			s += ","
+		}
+
+		s += shape(c)
+	}
+
+	if len(children) > 0 {
+		s += ")"
+	}
+
+	return s
+}
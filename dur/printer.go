@@ -0,0 +1,144 @@
+package dur
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type printer interface {
+	print(v1 interface{}, v2 interface{}, vr interface{}, op string)
+	printCalendar(lit string, from, to time.Time, vr duration)
+	printCall(name string, args []interface{}, vr interface{})
+	printRange(from, to clock, vr duration)
+}
+
+type discardPrinter struct{}
+
+func (p discardPrinter) print(_ interface{}, _ interface{}, _ interface{}, _ string) {
+}
+
+func (p discardPrinter) printCalendar(_ string, _, _ time.Time, _ duration) {
+}
+
+func (p discardPrinter) printCall(_ string, _ []interface{}, _ interface{}) {
+}
+
+func (p discardPrinter) printRange(_, _ clock, _ duration) {
+}
+
+type nanoPrinter struct{}
+
+func (p nanoPrinter) print(v1 interface{}, v2 interface{}, vr interface{}, op string) {
+	fmt.Printf("%18s %s %18s = %18s\n", nanoValue(v1), op, nanoValue(v2), nanoValue(vr))
+}
+
+func (p nanoPrinter) printCalendar(lit string, from, to time.Time, vr duration) {
+	fmt.Printf("%18s = %s .. %s = %18s\n", lit, from.Format(dateLayout), to.Format(dateLayout), nanoValue(vr))
+}
+
+func (p nanoPrinter) printCall(name string, args []interface{}, vr interface{}) {
+	fmt.Printf("%39s = %18s\n", formatCall(name, args, nanoValue), nanoValue(vr))
+}
+
+func (p nanoPrinter) printRange(from, to clock, vr duration) {
+	fmt.Printf("%39s = %18s\n", from.String()+rangeDots+to.String(), nanoValue(vr))
+}
+
+type humanReadablePrinter struct{}
+
+func (p humanReadablePrinter) print(v1 interface{}, v2 interface{}, vr interface{}, op string) {
+	fmt.Printf("%12s %s %12s = %12s\n", humanValue(v1), op, humanValue(v2), humanValue(vr))
+}
+
+func (p humanReadablePrinter) printCalendar(lit string, from, to time.Time, vr duration) {
+	fmt.Printf("%12s = %s .. %s = %12s\n", lit, from.Format(dateLayout), to.Format(dateLayout), humanValue(vr))
+}
+
+func (p humanReadablePrinter) printCall(name string, args []interface{}, vr interface{}) {
+	fmt.Printf("%27s = %12s\n", formatCall(name, args, humanValue), humanValue(vr))
+}
+
+func (p humanReadablePrinter) printRange(from, to clock, vr duration) {
+	fmt.Printf("%27s = %12s\n", from.String()+rangeDots+to.String(), humanValue(vr))
+}
+
+// formatCall formats a function call with its evaluated arguments.
+func formatCall(name string, args []interface{}, format func(interface{}) string) string {
+	var values = make([]string, len(args))
+	for i, arg := range args {
+		values[i] = format(arg)
+	}
+
+	return name + "(" + strings.Join(values, ", ") + ")"
+}
+
+// nanoValue formats durations as exact amount of nanoseconds and timestamps in RFC 3339.
+func nanoValue(v interface{}) string {
+	if l, ok := v.(list); ok {
+		return formatList(l, nanoValue)
+	}
+
+	if d, ok := v.(duration); ok {
+		return formatNumber(d.ns)
+	}
+
+	if d, ok := v.(time.Duration); ok {
+		return strconv.FormatInt(int64(d), 10)
+	}
+
+	if ts, ok := v.(timestamp); ok {
+		return ts.time(RoundTowardZero).Format(timestampLayout)
+	}
+
+	if t, ok := v.(time.Time); ok {
+		return t.Format(timestampLayout)
+	}
+
+	if c, ok := v.(clock); ok {
+		return c.String()
+	}
+
+	if p, ok := v.(percent); ok {
+		return p.String()
+	}
+
+	return formatNumber(v)
+}
+
+// humanValue formats durations like time.Duration, fractions of picoseconds are truncated.
+// Timestamps are formatted in RFC 3339.
+func humanValue(v interface{}) string {
+	if l, ok := v.(list); ok {
+		return formatList(l, humanValue)
+	}
+
+	if d, ok := v.(duration); ok {
+		return formatDuration(d)
+	}
+
+	if d, ok := v.(time.Duration); ok {
+		return d.String()
+	}
+
+	if ts, ok := v.(timestamp); ok {
+		return ts.time(RoundTowardZero).Format(timestampLayout)
+	}
+
+	if t, ok := v.(time.Time); ok {
+		return t.Format(timestampLayout)
+	}
+
+	if c, ok := v.(clock); ok {
+		return c.String()
+	}
+
+	if p, ok := v.(percent); ok {
+		return p.String()
+	}
+
+	return formatNumber(v)
+}
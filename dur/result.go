@@ -0,0 +1,127 @@
+package dur
+
+import (
+	"math/big"
+	"time"
+)
+
+// Result is the value an expression evaluates to. It is either a duration, a timestamp, a time of day, a dimensionless number,
+// like the ratio of two durations or a percentage, or a list of results.
+type Result struct {
+	v   interface{}
+	rem interface{}
+	f   formatter
+}
+
+// IsDuration reports whether the result is a duration.
+func (r Result) IsDuration() bool {
+	switch r.v.(type) {
+	case time.Duration, duration:
+		return true
+	default:
+		return false
+	}
+}
+
+// Duration returns the result if it is a duration that fits into time.Duration.
+// Fractions of nanoseconds of results evaluated with BigPrecision are truncated.
+func (r Result) Duration() (time.Duration, bool) {
+	switch d := r.v.(type) {
+	case time.Duration:
+		return d, true
+	case duration:
+		ns := truncate(d.ns)
+		if !ns.IsInt64() {
+			return 0, false
+		}
+
+		return time.Duration(ns.Int64()), true
+	default:
+		return 0, false
+	}
+}
+
+// Nanoseconds returns the exact amount of nanoseconds if the result is a duration.
+// Unlike Duration it is not limited in range.
+func (r Result) Nanoseconds() (*big.Rat, bool) {
+	switch d := r.v.(type) {
+	case time.Duration:
+		return toRat(d), true
+	case duration:
+		return new(big.Rat).Set(d.ns), true
+	default:
+		return nil, false
+	}
+}
+
+// Number returns the exact value of the result if it is a number. Percentages are returned as fraction.
+func (r Result) Number() (*big.Rat, bool) {
+	if p, ok := r.v.(percent); ok {
+		return new(big.Rat).Set(p.r), true
+	}
+
+	if !isScalar(r.v) {
+		return nil, false
+	}
+
+	return new(big.Rat).Set(toRat(r.v)), true
+}
+
+// IsTime reports whether the result is a timestamp.
+func (r Result) IsTime() bool {
+	_, ok := r.v.(time.Time)
+
+	return ok
+}
+
+// Time returns the result if it is a timestamp.
+func (r Result) Time() (time.Time, bool) {
+	t, ok := r.v.(time.Time)
+
+	return t, ok
+}
+
+// Clock returns the result as time since midnight if it is a time of day.
+func (r Result) Clock() (time.Duration, bool) {
+	c, ok := r.v.(clock)
+	if !ok {
+		return 0, false
+	}
+
+	return time.Duration(truncate(c.ns).Int64()), true
+}
+
+// List returns the elements of the result if it is a list.
+func (r Result) List() ([]Result, bool) {
+	l, ok := r.v.(list)
+	if !ok {
+		return nil, false
+	}
+
+	var elems = make([]Result, len(l))
+	for e, v := range l {
+		elems[e] = Result{v: v, f: r.f}
+	}
+
+	return elems, true
+}
+
+// Remainder returns the remainder of the division if the result was evaluated with DivMod.
+func (r Result) Remainder() (Result, bool) {
+	if r.rem == nil {
+		return Result{}, false
+	}
+
+	return Result{v: r.rem, f: r.f}, true
+}
+
+// String formats durations like time.Duration, without its range limit, or in the format set with Output,
+// numbers as decimals with up to 9 fractional digits, percentages with a % suffix, timestamps in RFC 3339,
+// times of day like 17:45 and lists like [1h0m0s, 2h0m0s]. A remainder is appended as "rem <remainder>".
+func (r Result) String() string {
+	if rem, ok := r.Remainder(); ok {
+		return Result{v: r.v, f: r.f}.String() + " rem " + rem.String()
+	}
+
+	return r.f.value(r.v)
+}
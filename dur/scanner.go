@@ -0,0 +1,430 @@
+package dur
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	TypeEOF        TokenType = "EOF"
+	TypeWhitespace TokenType = "WHITESPACE"
+	TypePlus       TokenType = "PLUS"
+	TypeMinus      TokenType = "MINUS"
+	TypeMultiply   TokenType = "MULTIPLY"
+	TypeDivide     TokenType = "DIVIDE"
+	TypeModulo     TokenType = "MODULO"
+	TypeParenOpen  TokenType = "PAREN_OPEN"
+	TypeParenClose TokenType = "PAREN_CLOSE"
+	TypeComma      TokenType = "COMMA"
+	TypeIdent      TokenType = "IDENT"
+	TypeAssign     TokenType = "ASSIGN"
+	TypeSemicolon  TokenType = "SEMICOLON"
+	TypeColon      TokenType = "COLON"
+	TypeDef        TokenType = "DEF"
+	TypeBrackOpen  TokenType = "BRACKET_OPEN"
+	TypeBrackClose TokenType = "BRACKET_CLOSE"
+	TypeRange      TokenType = "RANGE"
+
+	TypeDuration  = "DURATION"
+	TypeTimestamp = "TIMESTAMP"
+	TypeClock     = "CLOCK"
+	TypeInteger   = "INTEGER"
+	TypeDecimal   = "DECIMAL"
+	TypePercent   = "PERCENT"
+
+	TypeEmpty = ""
+)
+
+const (
+	minus      = '-'
+	plus       = '+'
+	multiply   = '*'
+	divide     = '/'
+	space      = ' '
+	parenOpen  = '('
+	parenClose = ')'
+	brackOpen  = '['
+	brackClose = ']'
+	comma      = ','
+	assign     = '='
+	colon      = ':'
+	semicolon  = ';'
+	newline    = '\n'
+	tab        = '\t'
+	cr         = '\r'
+
+	modulo    = "mod"
+	def       = "def"
+	rangeDots = ".."
+)
+
+type TokenType string
+
+type Token struct {
+	Type    TokenType
+	Literal string
+	Pos     int
+}
+
+func NewScanner(input string) *Scanner {
+	return &Scanner{
+		input: input,
+		pos:   0,
+		len:   len(input),
+	}
+}
+
+type Scanner struct {
+	input          string
+	pos            int
+	len            int
+	colonDurations bool
+	// separators holds for each open parenthesis or bracket whether a comma within it separates arguments or
+	// list elements, which is the case in calls and lists but not in plain groups like (1,5h)
+	separators []bool
+	prev       TokenType
+}
+
+func (s *Scanner) Tokens() ([]Token, error) {
+	var tokens []Token
+
+	for {
+		token, err := s.nextToken()
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Type == TypeWhitespace {
+			continue
+		}
+
+		tokens = append(tokens, token)
+
+		if token.Type == TypeEOF {
+			break
+		}
+	}
+
+	return tokens, nil
+}
+
+func (s *Scanner) eof(offset int) bool {
+	return s.pos+offset >= s.len
+}
+
+func (s *Scanner) peek(offset int) byte {
+	if s.eof(offset) {
+		panic(fmt.Sprintf("peek out of bounds at pos: %v, offset: %v", s.pos, offset))
+	}
+
+	return s.input[s.pos+offset]
+}
+
+func (s *Scanner) nextChar() {
+	s.pos++
+}
+
+func (s *Scanner) prevChar() {
+	s.pos--
+}
+
+func (s *Scanner) read() byte {
+	if s.eof(0) {
+		panic(fmt.Sprintf("read out of bounds at pos: %v", s.pos))
+	}
+
+	ch := s.input[s.pos]
+	s.nextChar()
+
+	return ch
+}
+
+// current returns the character at the current position or EOF for error messages.
+func (s *Scanner) current() string {
+	if s.eof(0) {
+		return string(TypeEOF)
+	}
+
+	return string(s.peek(0))
+}
+
+func (s *Scanner) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(s.input[s.pos:], prefix)
+}
+
+func (s *Scanner) nextToken() (Token, error) {
+	var (
+		tok Token
+		err error
+		pos = s.pos
+	)
+
+	if s.eof(0) {
+		return Token{Type: TypeEOF, Pos: pos}, nil
+	}
+
+	ch := s.peek(0)
+
+	switch {
+	case ch == space || ch == tab || ch == cr:
+		tok = Token{Type: TypeWhitespace}
+
+		s.nextChar()
+	case ch == semicolon || ch == newline:
+		tok = Token{Type: TypeSemicolon}
+
+		s.nextChar()
+	case ch == assign:
+		tok = Token{Type: TypeAssign}
+
+		s.nextChar()
+	case ch == colon:
+		tok = Token{Type: TypeColon}
+
+		s.nextChar()
+	case ch == minus:
+		tok = Token{Type: TypeMinus}
+
+		s.nextChar()
+	case ch == plus:
+		tok = Token{Type: TypePlus}
+
+		s.nextChar()
+	case ch == multiply:
+		tok = Token{Type: TypeMultiply}
+
+		s.nextChar()
+	case ch == divide:
+		tok = Token{Type: TypeDivide}
+
+		s.nextChar()
+	case ch == comma:
+		tok = Token{Type: TypeComma}
+
+		s.nextChar()
+	case s.hasPrefix(modulo) && (s.eof(len(modulo)) || !isLetter(s.peek(len(modulo)))):
+		tok = Token{Type: TypeModulo}
+
+		s.pos += len(modulo)
+	case findISO8601(s.input[s.pos:]) != "":
+		tok = Token{Type: TypeDuration, Literal: findISO8601(s.input[s.pos:])}
+
+		s.pos += len(tok.Literal)
+	case isLetter(ch):
+		tok = s.readIdent()
+	case ch == parenOpen:
+		tok = Token{Type: TypeParenOpen}
+		s.separators = append(s.separators, s.prev == TypeIdent)
+
+		s.nextChar()
+	case ch == parenClose:
+		tok = Token{Type: TypeParenClose}
+		s.closeNesting()
+
+		s.nextChar()
+	case ch == brackOpen:
+		tok = Token{Type: TypeBrackOpen}
+		s.separators = append(s.separators, true)
+
+		s.nextChar()
+	case ch == brackClose:
+		tok = Token{Type: TypeBrackClose}
+		s.closeNesting()
+
+		s.nextChar()
+	case s.hasPrefix(rangeDots):
+		tok = Token{Type: TypeRange}
+
+		s.pos += len(rangeDots)
+	case timestampPattern.MatchString(s.input[s.pos:]):
+		tok = Token{Type: TypeTimestamp, Literal: timestampPattern.FindString(s.input[s.pos:])}
+
+		s.pos += len(tok.Literal)
+	case s.isClock():
+		tok = Token{Type: TypeClock, Literal: clockPattern.FindString(s.input[s.pos:])}
+
+		s.pos += len(tok.Literal)
+	case isDigit(ch):
+		tok, err = s.readValue()
+	default:
+		err = newError(KindUnexpectedCharacter, pos, "unexpected character '%v'", string(ch))
+	}
+
+	if isDigit(ch) && err == nil && !s.eof(0) && isLetter(s.peek(0)) && !s.hasPrefix(modulo) {
+		err = newError(KindUnexpectedCharacter, s.pos, "unexpected character '%v'", s.current())
+	}
+
+	tok.Pos = pos
+
+	if tok.Type != TypeWhitespace {
+		s.prev = tok.Type
+	}
+
+	return tok, err
+}
+
+// closeNesting leaves the innermost parenthesis or bracket, unbalanced ones are left to the parser.
+func (s *Scanner) closeNesting() {
+	if len(s.separators) > 0 {
+		s.separators = s.separators[:len(s.separators)-1]
+	}
+}
+
+// commaSeparates reports whether a comma separates arguments or list elements rather than being a decimal comma.
+func (s *Scanner) commaSeparates() bool {
+	return len(s.separators) > 0 && s.separators[len(s.separators)-1]
+}
+
+func (s *Scanner) readValue() (Token, error) {
+	const (
+		uy   = 'y'
+		uo   = 'o'
+		uw   = 'w'
+		ud   = 'd'
+		uh   = 'h'
+		um   = 'm'
+		us   = 's'
+		umc  = 'u'
+		un   = 'n'
+		up   = 'p'
+		dec1 = ','
+		dec2 = '.'
+		pct  = '%'
+	)
+
+	var (
+		numDec = 0
+		sb     = strings.Builder{}
+		pos    = s.pos
+		ch     = s.read()
+	)
+
+	if !isDigit(ch) {
+		return Token{}, newError(KindUnexpectedCharacter, pos, "first character of value must be digit, got '%s'", string(ch))
+	}
+
+	sb.WriteByte(ch)
+
+loop:
+	for !s.eof(0) {
+		if alias := s.alias(); alias != "" {
+			// spoken units like 90 min, 2 hrs or 5 Minuten
+			sb.WriteString(alias)
+			s.pos += len(alias)
+
+			break
+		}
+
+		ch = s.read()
+		switch {
+		case ch == umc:
+			sb.WriteByte(ch)
+			if s.eof(0) || s.peek(0) != us {
+				return Token{}, newError(KindUnexpectedCharacter, s.pos, "invalid character for microseconds '%s'", s.current())
+			}
+			sb.WriteByte(s.read())
+
+			break loop
+		case ch == un:
+			sb.WriteByte(ch)
+			if s.eof(0) || s.peek(0) != us {
+				return Token{}, newError(KindUnexpectedCharacter, s.pos, "invalid character for nanoseconds '%s'", s.current())
+			}
+			sb.WriteByte(s.read())
+
+			break loop
+		case ch == up:
+			sb.WriteByte(ch)
+			if s.eof(0) || s.peek(0) != us {
+				return Token{}, newError(KindUnexpectedCharacter, s.pos, "invalid character for picoseconds '%s'", s.current())
+			}
+			sb.WriteByte(s.read())
+
+			break loop
+		case ch == pct:
+			sb.WriteByte(ch)
+
+			break loop
+		case ch == um && s.hasPrefix(modulo[1:]):
+			// 3mod2 is a modulo operation rather than 3mo followed by d2
+			s.prevChar()
+			break loop
+		case ch == uy || ch == uw || ch == ud || ch == uh || ch == um || ch == us:
+			sb.WriteByte(ch)
+			if !s.eof(0) && (s.peek(0) == us || s.peek(0) == uo) && ch == um {
+				sb.WriteByte(s.read())
+			}
+
+			break loop
+		case ch == dec1 && (s.commaSeparates() || s.eof(0) || !isDigit(s.peek(0))):
+			// within calls and lists a comma separates arguments and elements, so max(1,5) has two arguments
+			// and [1,2] two elements, just like a comma that is not followed by a digit, while (1,5h) is 1.5h
+			s.prevChar()
+			break loop
+		case (ch == dec1 || ch == dec2) && numDec == 0:
+			sb.WriteByte(ch)
+			numDec++
+		case ch == colon && colonPattern.MatchString(s.input[pos:]):
+			// 1:23:45 and 2.04:30:00 are durations in colon notation
+			s.pos = pos + len(colonPattern.FindString(s.input[pos:]))
+
+			return Token{Type: TypeDuration, Literal: s.input[pos:s.pos]}, nil
+		case isDigit(ch):
+			sb.WriteByte(ch)
+		default:
+			s.prevChar()
+			break loop
+		}
+	}
+
+	var value = sb.String()
+	if value[len(value)-1] == pct {
+		return Token{Type: TypePercent, Literal: value}, nil
+	}
+
+	if isDigit(value[len(value)-1]) && numDec > 0 {
+		return Token{Type: TypeDecimal, Literal: value}, nil
+	}
+
+	if isDigit(value[len(value)-1]) {
+		return Token{Type: TypeInteger, Literal: value}, nil
+	}
+
+	return Token{Type: TypeDuration, Literal: value}, nil
+}
+
+// isClock reports whether a time of day like 09:15 starts at the current position. Colon literals with days
+// or fractions of seconds like 2.04:30:00 or 1:23:45.5 are durations, with colonDurations all of them are.
+func (s *Scanner) isClock() bool {
+	if s.colonDurations {
+		return false
+	}
+
+	lit := clockPattern.FindString(s.input[s.pos:])
+
+	return lit != "" && lit == colonPattern.FindString(s.input[s.pos:])
+}
+
+// readIdent reads the name of a function or variable. The keyword def is returned as TypeDef.
+func (s *Scanner) readIdent() Token {
+	var start = s.pos
+
+	for !s.eof(0) && (isLetter(s.peek(0)) || isDigit(s.peek(0))) {
+		s.nextChar()
+	}
+
+	var name = s.input[start:s.pos]
+	if name == def {
+		return Token{Type: TypeDef}
+	}
+
+	return Token{Type: TypeIdent, Literal: name}
+}
+
+func isDigit(ch byte) bool {
+	return ch >= 48 && ch <= 57
+}
+
+func isLetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch == '_'
+}
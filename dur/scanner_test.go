@@ -0,0 +1,111 @@
+package dur_test
+
+import (
+	"errors"
+	"github.com/Oppodelldog/dur/dur"
+	"reflect"
+	"testing"
+)
+
+func TestScanner_Tokens(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  []dur.Token
+	}
+
+	tests := []testCase{
+		{name: "empty input", input: "", want: []dur.Token{{Type: dur.TypeEOF, Pos: 0}}},
+		{name: "operators", input: "+-*/", want: []dur.Token{{Type: dur.TypePlus, Pos: 0}, {Type: dur.TypeMinus, Pos: 1}, {Type: dur.TypeMultiply, Pos: 2}, {Type: dur.TypeDivide, Pos: 3}, {Type: dur.TypeEOF, Pos: 4}}},
+		{name: "parentheses", input: "()", want: []dur.Token{{Type: dur.TypeParenOpen, Pos: 0}, {Type: dur.TypeParenClose, Pos: 1}, {Type: dur.TypeEOF, Pos: 2}}},
+		{name: "years", input: "1y", want: []dur.Token{{Type: dur.TypeDuration, Literal: "1y", Pos: 0}, {Type: dur.TypeEOF, Pos: 2}}},
+		{name: "months", input: "3mo2w", want: []dur.Token{{Type: dur.TypeDuration, Literal: "3mo", Pos: 0}, {Type: dur.TypeDuration, Literal: "2w", Pos: 3}, {Type: dur.TypeEOF, Pos: 5}}},
+		{name: "weeks", input: "12w", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12w", Pos: 0}, {Type: dur.TypeEOF, Pos: 3}}},
+		{name: "days", input: "1,5d", want: []dur.Token{{Type: dur.TypeDuration, Literal: "1,5d", Pos: 0}, {Type: dur.TypeEOF, Pos: 4}}},
+		{name: "hours", input: "12h", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12h", Pos: 0}, {Type: dur.TypeEOF, Pos: 3}}},
+		{name: "minutes", input: "12m", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12m", Pos: 0}, {Type: dur.TypeEOF, Pos: 3}}},
+		{name: "seconds", input: "12s", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12s", Pos: 0}, {Type: dur.TypeEOF, Pos: 3}}},
+		{name: "milliseconds", input: "12ms", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12ms", Pos: 0}, {Type: dur.TypeEOF, Pos: 4}}},
+		{name: "milliseconds", input: "12us", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12us", Pos: 0}, {Type: dur.TypeEOF, Pos: 4}}},
+		{name: "picoseconds", input: "12ps", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12ps", Pos: 0}, {Type: dur.TypeEOF, Pos: 4}}},
+		{name: "nanoseconds", input: "12ns", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12ns", Pos: 0}, {Type: dur.TypeEOF, Pos: 4}}},
+		{name: "hours floating number 1", input: "12,5h", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12,5h", Pos: 0}, {Type: dur.TypeEOF, Pos: 5}}},
+		{name: "hours floating number 2", input: "12,333333h", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12,333333h", Pos: 0}, {Type: dur.TypeEOF, Pos: 10}}},
+		{name: "hours floating number 3", input: "12.333333h", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12.333333h", Pos: 0}, {Type: dur.TypeEOF, Pos: 10}}},
+		{name: "combined values", input: "12h11m2s", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12h", Pos: 0}, {Type: dur.TypeDuration, Literal: "11m", Pos: 3}, {Type: dur.TypeDuration, Literal: "2s", Pos: 6}, {Type: dur.TypeEOF, Pos: 8}}},
+		{name: "function call", input: "ceil(1h30m,15m)", want: []dur.Token{{Type: dur.TypeIdent, Literal: "ceil", Pos: 0}, {Type: dur.TypeParenOpen, Pos: 4}, {Type: dur.TypeDuration, Literal: "1h", Pos: 5}, {Type: dur.TypeDuration, Literal: "30m", Pos: 7}, {Type: dur.TypeComma, Pos: 10}, {Type: dur.TypeDuration, Literal: "15m", Pos: 11}, {Type: dur.TypeParenClose, Pos: 14}, {Type: dur.TypeEOF, Pos: 15}}},
+		{name: "comma after integer", input: "f(2, 3)", want: []dur.Token{{Type: dur.TypeIdent, Literal: "f", Pos: 0}, {Type: dur.TypeParenOpen, Pos: 1}, {Type: dur.TypeInteger, Literal: "2", Pos: 2}, {Type: dur.TypeComma, Pos: 3}, {Type: dur.TypeInteger, Literal: "3", Pos: 5}, {Type: dur.TypeParenClose, Pos: 6}, {Type: dur.TypeEOF, Pos: 7}}},
+		{name: "identifier with digits", input: "f2_x", want: []dur.Token{{Type: dur.TypeIdent, Literal: "f2_x", Pos: 0}, {Type: dur.TypeEOF, Pos: 4}}},
+		{name: "identifier starting with mod", input: "model", want: []dur.Token{{Type: dur.TypeIdent, Literal: "model", Pos: 0}, {Type: dur.TypeEOF, Pos: 5}}},
+		{name: "statements", input: "a = 1h;a\n", want: []dur.Token{{Type: dur.TypeIdent, Literal: "a", Pos: 0}, {Type: dur.TypeAssign, Pos: 2}, {Type: dur.TypeDuration, Literal: "1h", Pos: 4}, {Type: dur.TypeSemicolon, Pos: 6}, {Type: dur.TypeIdent, Literal: "a", Pos: 7}, {Type: dur.TypeSemicolon, Pos: 8}, {Type: dur.TypeEOF, Pos: 9}}},
+		{name: "definition", input: "def f(x: duration)", want: []dur.Token{{Type: dur.TypeDef, Pos: 0}, {Type: dur.TypeIdent, Literal: "f", Pos: 4}, {Type: dur.TypeParenOpen, Pos: 5}, {Type: dur.TypeIdent, Literal: "x", Pos: 6}, {Type: dur.TypeColon, Pos: 7}, {Type: dur.TypeIdent, Literal: "duration", Pos: 9}, {Type: dur.TypeParenClose, Pos: 17}, {Type: dur.TypeEOF, Pos: 18}}},
+		{name: "list", input: "[1h,2]", want: []dur.Token{{Type: dur.TypeBrackOpen, Pos: 0}, {Type: dur.TypeDuration, Literal: "1h", Pos: 1}, {Type: dur.TypeComma, Pos: 3}, {Type: dur.TypeInteger, Literal: "2", Pos: 4}, {Type: dur.TypeBrackClose, Pos: 5}, {Type: dur.TypeEOF, Pos: 6}}},
+		{name: "date", input: "2026-10-18+1h", want: []dur.Token{{Type: dur.TypeTimestamp, Literal: "2026-10-18", Pos: 0}, {Type: dur.TypePlus, Pos: 10}, {Type: dur.TypeDuration, Literal: "1h", Pos: 11}, {Type: dur.TypeEOF, Pos: 13}}},
+		{name: "date and time", input: "2026-10-18 09:12 + 1h", want: []dur.Token{{Type: dur.TypeTimestamp, Literal: "2026-10-18 09:12", Pos: 0}, {Type: dur.TypePlus, Pos: 17}, {Type: dur.TypeDuration, Literal: "1h", Pos: 19}, {Type: dur.TypeEOF, Pos: 21}}},
+		{name: "clock range", input: "9:15-17:45:30", want: []dur.Token{{Type: dur.TypeClock, Literal: "9:15", Pos: 0}, {Type: dur.TypeMinus, Pos: 4}, {Type: dur.TypeClock, Literal: "17:45:30", Pos: 5}, {Type: dur.TypeEOF, Pos: 13}}},
+		{name: "clock range with dots", input: "22:00..06:00", want: []dur.Token{{Type: dur.TypeClock, Literal: "22:00", Pos: 0}, {Type: dur.TypeRange, Pos: 5}, {Type: dur.TypeClock, Literal: "06:00", Pos: 7}, {Type: dur.TypeEOF, Pos: 12}}},
+		{name: "colon duration with days", input: "2.04:30:00+1:23:45.5", want: []dur.Token{{Type: dur.TypeDuration, Literal: "2.04:30:00", Pos: 0}, {Type: dur.TypePlus, Pos: 10}, {Type: dur.TypeDuration, Literal: "1:23:45.5", Pos: 11}, {Type: dur.TypeEOF, Pos: 20}}},
+		{name: "iso 8601", input: "P3DT4H-PT0,5S", want: []dur.Token{{Type: dur.TypeDuration, Literal: "P3DT4H", Pos: 0}, {Type: dur.TypeMinus, Pos: 6}, {Type: dur.TypeDuration, Literal: "PT0,5S", Pos: 7}, {Type: dur.TypeEOF, Pos: 13}}},
+		{name: "identifier like iso 8601", input: "PT PT1Hx", want: []dur.Token{{Type: dur.TypeIdent, Literal: "PT", Pos: 0}, {Type: dur.TypeIdent, Literal: "PT1Hx", Pos: 3}, {Type: dur.TypeEOF, Pos: 8}}},
+		{name: "unit names", input: "1 Hour 30mins+2\tStd", want: []dur.Token{{Type: dur.TypeDuration, Literal: "1 Hour", Pos: 0}, {Type: dur.TypeDuration, Literal: "30mins", Pos: 7}, {Type: dur.TypePlus, Pos: 13}, {Type: dur.TypeDuration, Literal: "2\tStd", Pos: 14}, {Type: dur.TypeEOF, Pos: 19}}},
+		{name: "call after number", input: "2 min(1h)", want: []dur.Token{{Type: dur.TypeInteger, Literal: "2", Pos: 0}, {Type: dur.TypeIdent, Literal: "min", Pos: 2}, {Type: dur.TypeParenOpen, Pos: 5}, {Type: dur.TypeDuration, Literal: "1h", Pos: 6}, {Type: dur.TypeParenClose, Pos: 8}, {Type: dur.TypeEOF, Pos: 9}}},
+		{name: "comma within call", input: "max(1,2)", want: []dur.Token{{Type: dur.TypeIdent, Literal: "max", Pos: 0}, {Type: dur.TypeParenOpen, Pos: 3}, {Type: dur.TypeInteger, Literal: "1", Pos: 4}, {Type: dur.TypeComma, Pos: 5}, {Type: dur.TypeInteger, Literal: "2", Pos: 6}, {Type: dur.TypeParenClose, Pos: 7}, {Type: dur.TypeEOF, Pos: 8}}},
+		{name: "decimal comma within group", input: "(1,5h)", want: []dur.Token{{Type: dur.TypeParenOpen, Pos: 0}, {Type: dur.TypeDuration, Literal: "1,5h", Pos: 1}, {Type: dur.TypeParenClose, Pos: 5}, {Type: dur.TypeEOF, Pos: 6}}},
+		{name: "rfc 3339", input: "2026-10-18T09:12:00.5Z-2026-10-18T08:00:00+01:00", want: []dur.Token{{Type: dur.TypeTimestamp, Literal: "2026-10-18T09:12:00.5Z", Pos: 0}, {Type: dur.TypeMinus, Pos: 22}, {Type: dur.TypeTimestamp, Literal: "2026-10-18T08:00:00+01:00", Pos: 23}, {Type: dur.TypeEOF, Pos: 48}}},
+		{name: "decimals", input: "1.5*2,25", want: []dur.Token{{Type: dur.TypeDecimal, Literal: "1.5", Pos: 0}, {Type: dur.TypeMultiply, Pos: 3}, {Type: dur.TypeDecimal, Literal: "2,25", Pos: 4}, {Type: dur.TypeEOF, Pos: 8}}},
+		{name: "modulo", input: "3h mod 25m", want: []dur.Token{{Type: dur.TypeDuration, Literal: "3h", Pos: 0}, {Type: dur.TypeModulo, Pos: 3}, {Type: dur.TypeDuration, Literal: "25m", Pos: 7}, {Type: dur.TypeEOF, Pos: 10}}},
+		{name: "modulo without whitespace", input: "180mmod7mod2", want: []dur.Token{{Type: dur.TypeDuration, Literal: "180m", Pos: 0}, {Type: dur.TypeModulo, Pos: 4}, {Type: dur.TypeInteger, Literal: "7", Pos: 7}, {Type: dur.TypeModulo, Pos: 8}, {Type: dur.TypeInteger, Literal: "2", Pos: 11}, {Type: dur.TypeEOF, Pos: 12}}},
+		{name: "percent", input: "8h+12,5%", want: []dur.Token{{Type: dur.TypeDuration, Literal: "8h", Pos: 0}, {Type: dur.TypePlus, Pos: 2}, {Type: dur.TypePercent, Literal: "12,5%", Pos: 3}, {Type: dur.TypeEOF, Pos: 8}}},
+		{name: "whitespace", input: "1h + 2m", want: []dur.Token{{Type: dur.TypeDuration, Literal: "1h", Pos: 0}, {Type: dur.TypePlus, Pos: 3}, {Type: dur.TypeDuration, Literal: "2m", Pos: 5}, {Type: dur.TypeEOF, Pos: 7}}},
+		{name: "combined durations with operators", input: "12h-11m+10m*4/2", want: []dur.Token{{Type: dur.TypeDuration, Literal: "12h", Pos: 0}, {Type: dur.TypeMinus, Pos: 3}, {Type: dur.TypeDuration, Literal: "11m", Pos: 4}, {Type: dur.TypePlus, Pos: 7}, {Type: dur.TypeDuration, Literal: "10m", Pos: 8}, {Type: dur.TypeMultiply, Pos: 11}, {Type: dur.TypeInteger, Literal: "4", Pos: 12}, {Type: dur.TypeDivide, Pos: 13}, {Type: dur.TypeInteger, Literal: "2", Pos: 14}, {Type: dur.TypeEOF, Pos: 15}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := dur.NewScanner(tt.input)
+			got, err := s.Tokens()
+			if err != nil {
+				t.Fatalf("Tokens() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_Tokens_Errors(t *testing.T) {
+	type testCase struct {
+		name   string
+		input  string
+		want   string
+		offset int
+	}
+
+	tests := []testCase{
+		{name: "unknown character", input: "1h #", want: "unexpected character '#'", offset: 3},
+		{name: "unknown unit", input: "1q", want: "unexpected character 'q'", offset: 1},
+		{name: "letter after timestamp", input: "2026-10-18x", want: "unexpected character 'x'", offset: 10},
+		{name: "letter after unit", input: "1hx", want: "unexpected character 'x'", offset: 2},
+		{name: "unknown unit after m", input: "1mx", want: "unexpected character 'x'", offset: 2},
+		{name: "second decimal separator", input: "1.2.3h", want: "unexpected character '.'", offset: 3},
+		{name: "incomplete microseconds", input: "1u", want: "invalid character for microseconds 'EOF'", offset: 2},
+		{name: "incomplete picoseconds", input: "1px", want: "invalid character for picoseconds 'x'", offset: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dur.NewScanner(tt.input).Tokens()
+
+			var scanErr *dur.Error
+			if !errors.As(err, &scanErr) {
+				t.Fatalf("Tokens() error = %v, want *dur.Error", err)
+			}
+
+			if scanErr.Kind != dur.KindUnexpectedCharacter || scanErr.Error() != tt.want || scanErr.Offset != tt.offset {
+				t.Errorf("Tokens() error = %v (%v at %v), want %v at %v", scanErr, scanErr.Kind, scanErr.Offset, tt.want, tt.offset)
+			}
+		})
+	}
+}
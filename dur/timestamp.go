@@ -0,0 +1,78 @@
+package dur
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// timestampPattern matches timestamps in RFC 3339 format or like 2026-10-18 09:12.
+var timestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:\d{2})?)?`)
+
+var timestampLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// timestampLayout is used to print timestamps.
+const timestampLayout = time.RFC3339Nano
+
+// timestamp is a point in time as exact amount of nanoseconds since the Unix epoch. Adding a duration
+// keeps fractions of nanoseconds, the result is rounded only once like durations are.
+type timestamp struct {
+	ns  *big.Rat
+	loc *time.Location
+}
+
+func timestampOf(t time.Time) timestamp {
+	ns := new(big.Int).Mul(big.NewInt(t.Unix()), big.NewInt(int64(time.Second)))
+	ns.Add(ns, big.NewInt(int64(t.Nanosecond())))
+
+	return timestamp{ns: new(big.Rat).SetInt(ns), loc: t.Location()}
+}
+
+// parseTimestamp parses a timestamp literal. Timestamps without zone are in UTC.
+func parseTimestamp(lit string) (timestamp, error) {
+	var s = strings.Replace(lit, " ", "T", 1)
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return timestampOf(t), nil
+		}
+	}
+
+	return timestamp{}, fmt.Errorf("invalid timestamp %v", lit)
+}
+
+// time rounds ts to whole nanoseconds with mode.
+func (ts timestamp) time(mode RoundingMode) time.Time {
+	sec, nsec := new(big.Int).DivMod(mode.round(ts.ns), big.NewInt(int64(time.Second)), new(big.Int))
+
+	return time.Unix(sec.Int64(), nsec.Int64()).In(ts.loc)
+}
+
+// applyTime adds durations to or subtracts them from timestamps. The difference of two timestamps is a duration.
+func applyTime(op Token, v1, v2 interface{}) (interface{}, error) {
+	t1, isTime1 := v1.(timestamp)
+	t2, isTime2 := v2.(timestamp)
+	d1, isDur1 := v1.(duration)
+	d2, isDur2 := v2.(duration)
+
+	switch {
+	case op.Type == TypePlus && isTime1 && isDur2:
+		return timestamp{ns: new(big.Rat).Add(t1.ns, d2.ns), loc: t1.loc}, nil
+	case op.Type == TypePlus && isDur1 && isTime2:
+		return timestamp{ns: new(big.Rat).Add(d1.ns, t2.ns), loc: t2.loc}, nil
+	case op.Type == TypeMinus && isTime1 && isDur2:
+		return timestamp{ns: new(big.Rat).Sub(t1.ns, d2.ns), loc: t1.loc}, nil
+	case op.Type == TypeMinus && isTime1 && isTime2:
+		return duration{ns: new(big.Rat).Sub(t1.ns, t2.ns)}, nil
+	default:
+		return nil, newTokenError(KindInvalidOperation, op, "cannot calculate %v %v %v", typeName(v1), opSymbol(op.Type), typeName(v2))
+	}
+}
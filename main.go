@@ -1,25 +1,63 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"github.com/Oppodelldog/dur/internal"
+	"github.com/Oppodelldog/dur/dur"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+var outputFormats = map[string]dur.OutputFormat{
+	"default": dur.FormatDefault,
+	"colon":   dur.FormatColon,
+	"iso8601": dur.FormatISO8601,
+	"decimal": dur.FormatDecimal,
+	"compact": dur.FormatCompact,
+	"int":     dur.FormatInt,
+	"human":   dur.FormatHuman,
+}
+
+var roundingModes = map[string]dur.RoundingMode{
+	"zero":      dur.RoundTowardZero,
+	"half-away": dur.RoundHalfAwayFromZero,
+	"half-even": dur.RoundHalfEven,
+	"floor":     dur.RoundFloor,
+	"ceil":      dur.RoundCeil,
+}
+
 func main() {
 	var (
-		options []internal.Option
-		fs      = flag.NewFlagSet("dur", flag.ExitOnError)
-		printer = fs.String("p", "", "prints a line for each calculation that is performed.\nOutput options:\n  h - human readable\n  n - nanoseconds\nexample: -p=h")
+		options  []dur.Option
+		fs       = flag.NewFlagSet("dur", flag.ExitOnError)
+		printer  = fs.String("p", "", "prints a line for each calculation that is performed.\nOutput options:\n  h - human readable\n  n - nanoseconds\nexample: -p=h")
+		days     = fs.String("days", "calendar", "length of the units d and w.\n  calendar - 24h and 168h\n  work - work days and weeks of 5 work days, see -workday")
+		workday  = fs.Duration("workday", 8*time.Hour, "length of a work day, used with -days=work")
+		from     = fs.String("from", "", "anchor date for the units mo and y, format 2006-01-02 or RFC 3339\nexample: -from=2026-01-31")
+		divMod   = fs.Bool("divmod", false, "prints quotient and remainder of a division\nexample: -divmod 3h/25m")
+		rounding = fs.String("rounding", "zero", "how the exact result is rounded to whole nanoseconds.\n  zero - toward zero\n  half-away - to nearest, halfway away from zero\n  half-even - to nearest, halfway to even\n  floor - toward negative infinity\n  ceil - toward positive infinity")
+		saturate = fs.Bool("saturate", false, "clamps values exceeding the range of a duration instead of failing\nexample: -saturate 2000000h*2")
+		prec     = fs.String("precision", "nano", "numeric backend of durations and the number of fractional digits of -o=decimal, separated by a comma.\n  nano - nanoseconds within the range of a duration, about ±292 years\n  big - picoseconds without range limit\n  0, 1, 2, ... - fractional digits, rounded half away from zero\nexample: -precision=big 1000*365d, -o=decimal -u=d -precision=big,2 1000*365d")
+		defs     = fs.String("defs", "", "file with variables and functions available to the expression\nexample: -defs=team.dur 'overtime(9h30m)'")
+		colon    = fs.String("colon", "clock", "meaning of values like 1:30.\n  clock - times of day, unless they have days or fractions of seconds like 2.04:30:00\n  duration - durations of [d.]h:mm[:ss[.fff]]\nexample: -colon=duration 1:23:45 + 0:40")
+		output   = fs.String("o", "default", "output format of durations.\n  default - like 1h30m0s\n  colon - [d.]h:mm:ss[.fff] like 1:30:00\n  iso8601 - ISO 8601 like PT1H30M\n  decimal - decimal number of a unit like 8.25h, see -u, -precision and -max-unit\n  compact - without zero values like 12h, see -max-unit\n  int - whole number of a unit without suffix like 43200, see -u\n  human - in words like 2 hours 30 minutes, see -lang, -round, -approx and -max-unit\nexample: -o=colon 1h30m")
+		unit     = fs.String("u", "", "unit of -o=decimal and -o=int like h or s.\nexample: -o=decimal -u=h -precision=2 8h15m")
+		maxUnit  = fs.String("max-unit", "h", "largest unit of -o=decimal, -o=compact and -o=human like d or w.\nexample: -o=compact -max-unit=d 36h")
+		lang     = fs.String("lang", "en", "language of -o=human.\n  en - English\n  de - German\nexample: -o=human -lang=de 2h30m")
+		round    = fs.Duration("round", 0, "granularity of -o=human, the duration is rounded to a multiple of it\nexample: -o=human -round=1m 2h29m40s")
+		approx   = fs.Bool("approx", false, "rounds -o=human to the largest unit, like about 3 hours\nexample: -o=human -approx 2h40m")
+		legacy   = fs.Bool("legacy-precedence", false, "evaluates all operators strictly from left to right, so 10m+20m*2 yields 1h0m0s")
 	)
 
 	fs.Usage = usage(fs)
 
 	var err = fs.Parse(os.Args[1:])
 
-	input := strings.Join(os.Args[len(os.Args)-fs.NArg():], "")
+	input := joinArgs(os.Args[len(os.Args)-fs.NArg():])
 
 	if len(input) == 0 || err != nil {
 		fs.Usage()
@@ -27,12 +65,160 @@ func main() {
 
 	switch *printer {
 	case "h":
-		options = append(options, internal.HumanReadablePrinter)
+		options = append(options, dur.HumanReadablePrinter)
 	case "n":
-		options = append(options, internal.NanoPrinter)
+		options = append(options, dur.NanoPrinter)
+	}
+
+	switch *days {
+	case "calendar":
+		options = append(options, dur.CalendarDays)
+	case "work":
+		options = append(options, dur.DayLength(*workday, 5**workday))
+	default:
+		fmt.Fprintf(os.Stderr, "dur: invalid value '%v' for -days\n", *days)
+		os.Exit(2)
+	}
+
+	if *from != "" {
+		anchor, err := parseDate(*from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dur: invalid value '%v' for -from\n", *from)
+			os.Exit(2)
+		}
+
+		options = append(options, dur.From(anchor))
+	}
+
+	if *divMod {
+		options = append(options, dur.DivMod)
+	}
+
+	mode, ok := roundingModes[*rounding]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "dur: invalid value '%v' for -rounding\n", *rounding)
+		os.Exit(2)
+	}
+
+	options = append(options, dur.Rounding(mode))
+
+	if *saturate {
+		options = append(options, dur.Saturate)
+	}
+
+	for _, value := range strings.Split(*prec, ",") {
+		switch digits, err := strconv.Atoi(value); {
+		case value == "nano":
+		case value == "big":
+			options = append(options, dur.BigPrecision)
+		case err == nil && digits >= 0:
+			options = append(options, dur.Decimals(digits))
+		default:
+			fmt.Fprintf(os.Stderr, "dur: invalid value '%v' for -precision\n", *prec)
+			os.Exit(2)
+		}
+	}
+
+	switch *colon {
+	case "clock":
+	case "duration":
+		options = append(options, dur.ColonDurations)
+	default:
+		fmt.Fprintf(os.Stderr, "dur: invalid value '%v' for -colon\n", *colon)
+		os.Exit(2)
+	}
+
+	format, ok := outputFormats[*output]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "dur: invalid value '%v' for -o\n", *output)
+		os.Exit(2)
+	}
+
+	options = append(options, dur.Output(format), dur.OutputUnit(*unit), dur.MaxUnit(*maxUnit))
+	options = append(options, dur.Language(*lang), dur.Granularity(*round))
+
+	if *approx {
+		options = append(options, dur.Approximate)
+	}
+
+	if *legacy {
+		options = append(options, dur.LegacyPrecedence)
+	}
+
+	if *defs != "" {
+		env, err := loadDefs(*defs, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, errorMessage(err))
+			os.Exit(1)
+		}
+
+		options = append(options, dur.Environment(env))
+	}
+
+	result, err := dur.Evaluate(input, options...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorMessage(err))
+		os.Exit(1)
+	}
+
+	fmt.Println(result)
+}
+
+// timeSuffix matches arguments ending with a date or a time of day like 2026-10-18 or 12:00.
+var timeSuffix = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}|\d:\d{2}(:\d{2}(\.\d+)?)?)$`)
+
+// joinArgs joins the arguments of the expression without separator, so dur 1 5m is 15m. Only a date or time of day
+// keeps a space before a following digit, so 2026-10-18 09:12 and 08:30-12:00 12:45-17:15 stay apart.
+func joinArgs(args []string) string {
+	var sb strings.Builder
+
+	for n, arg := range args {
+		if n > 0 && arg != "" && arg[0] >= '0' && arg[0] <= '9' && timeSuffix.MatchString(args[n-1]) {
+			sb.WriteString(" ")
+		}
+
+		sb.WriteString(arg)
+	}
+
+	return sb.String()
+}
+
+func loadDefs(name string, options []dur.Option) (*dur.Env, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	env := dur.NewEnv(nil)
+	if err := env.Load(f, options...); err != nil {
+		var calcErr *dur.Error
+		if errors.As(err, &calcErr) {
+			calcErr.Message += " in " + name
+		}
+
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
+
+func errorMessage(err error) string {
+	var calcErr *dur.Error
+	if errors.As(err, &calcErr) {
+		return fmt.Sprintf("dur: %v at offset %v", calcErr, calcErr.Offset)
 	}
 
-	fmt.Println(internal.NewCalculator(input, options...).Calculate())
+	return fmt.Sprintf("dur: %v", err)
 }
 
 func usage(fs *flag.FlagSet) func() {